@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lifinance/lifi-mcp/server"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPath returns ~/.lifi-mcp/config.yaml, or "" if the home directory is unknown
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".lifi-mcp", "config.yaml")
+}
+
+// envAliases are established environment variables that also set a flag. They rank below
+// the config file: they are often exported for other tools, while the file is written
+// for this server.
+var envAliases = map[string]string{
+	"api-key": "LIFI_API_KEY",
+}
+
+// envVarForFlag returns the environment variable that overrides a flag, e.g.
+// LIFI_MCP_LOG_LEVEL for --log-level
+func envVarForFlag(name string) string {
+	return "LIFI_MCP_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyConfig fills in every flag not set on the command line, first from its
+// LIFI_MCP_* environment variable, then from the YAML config file, whose keys are the
+// flag names, and last from its envAliases variable. It returns the flags that were set
+// from an alias. A missing file is only an error if the path was given explicitly.
+func applyConfig(fs *flag.FlagSet, path string, explicit bool) (map[string]bool, error) {
+	values := map[string]interface{}{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+		case err != nil:
+			return nil, fmt.Errorf("failed to read config file: %v", err)
+		default:
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+			}
+		}
+	}
+
+	setOnCommandLine := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+
+	// Reject unknown keys so typos don't silently fall back to defaults
+	var unknown []string
+	for key := range values {
+		if fs.Lookup(key) == nil || key == "config" || key == "version" {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown keys in config file %s: %s", path, strings.Join(unknown, ", "))
+	}
+
+	var err error
+	fromAlias := map[string]bool{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || setOnCommandLine[f.Name] || f.Name == "config" || f.Name == "version" {
+			return
+		}
+		if v, ok := os.LookupEnv(envVarForFlag(f.Name)); ok {
+			if setErr := fs.Set(f.Name, v); setErr != nil {
+				err = fmt.Errorf("invalid value for %s: %v", envVarForFlag(f.Name), setErr)
+			}
+			return
+		}
+		if v, ok := values[f.Name]; ok {
+			for _, item := range configValues(v) {
+				if setErr := fs.Set(f.Name, item); setErr != nil {
+					err = fmt.Errorf("invalid value for %q in config file: %v", f.Name, setErr)
+					return
+				}
+			}
+			return
+		}
+		if key := envAliases[f.Name]; key != "" {
+			if v, ok := os.LookupEnv(key); ok {
+				if setErr := fs.Set(f.Name, v); setErr != nil {
+					err = fmt.Errorf("invalid value for %s: %v", key, setErr)
+				}
+				fromAlias[f.Name] = true
+			}
+		}
+	})
+	return fromAlias, err
+}
+
+// configValues flattens a config value into the strings to pass to flag.Set. Lists
+// set a repeatable flag once per item and maps once per "key=value" pair.
+func configValues(v interface{}) []string {
+	switch val := v.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	case map[string]interface{}:
+		items := make([]string, 0, len(val))
+		for k, item := range val {
+			items = append(items, fmt.Sprintf("%s=%v", k, item))
+		}
+		sort.Strings(items)
+		return items
+	case map[interface{}]interface{}:
+		items := make([]string, 0, len(val))
+		for k, item := range val {
+			items = append(items, fmt.Sprintf("%v=%v", k, item))
+		}
+		sort.Strings(items)
+		return items
+	}
+	return []string{fmt.Sprint(v)}
+}
+
+// rpcOverridesFlag collects repeatable --rpc chainId=url flags
+type rpcOverridesFlag map[int]string
+
+func (f rpcOverridesFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for id, url := range f {
+		pairs = append(pairs, fmt.Sprintf("%d=%s", id, url))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set parses one or more comma-separated chainId=url pairs
+func (f rpcOverridesFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, url, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected chainId=url, got %q", pair)
+		}
+		chainID, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil || chainID <= 0 {
+			return fmt.Errorf("invalid chain ID %q", id)
+		}
+		url = strings.TrimSpace(url)
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") &&
+			!strings.HasPrefix(url, "wss://") && !strings.HasPrefix(url, "ws://") {
+			return fmt.Errorf("invalid RPC URL for chain %d: must be http(s) or ws(s)", chainID)
+		}
+		f[chainID] = url
+	}
+	return nil
+}
+
+// toolPatternsFlag collects repeatable, comma-separated tool name patterns
+type toolPatternsFlag []string
+
+func (f *toolPatternsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set parses one or more comma-separated glob patterns such as "get-nft-*"
+func (f *toolPatternsFlag) Set(value string) error {
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q", pattern)
+		}
+		*f = append(*f, pattern)
+	}
+	return nil
+}
+
+// toolLimitsFlag collects repeatable, comma-separated pattern=calls/period tool budgets
+type toolLimitsFlag []server.ToolLimit
+
+func (f *toolLimitsFlag) String() string {
+	limits := make([]string, 0, len(*f))
+	for _, limit := range *f {
+		limits = append(limits, limit.String())
+	}
+	return strings.Join(limits, ",")
+}
+
+// Set parses one or more comma-separated budgets such as "transfer-nft=20/24h"
+func (f *toolLimitsFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		limit, err := server.ParseToolLimit(item)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, limit)
+	}
+	return nil
+}
+
+// toolTimeoutsFlag collects repeatable, comma-separated pattern=duration tool timeouts
+type toolTimeoutsFlag []server.ToolTimeout
+
+func (f *toolTimeoutsFlag) String() string {
+	timeouts := make([]string, 0, len(*f))
+	for _, timeout := range *f {
+		timeouts = append(timeouts, timeout.String())
+	}
+	return strings.Join(timeouts, ",")
+}
+
+// Set parses one or more comma-separated timeouts such as "get-*=15s"
+func (f *toolTimeoutsFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		timeout, err := server.ParseToolTimeout(item)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, timeout)
+	}
+	return nil
+}
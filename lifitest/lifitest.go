@@ -0,0 +1,179 @@
+// Package lifitest is a test harness for the LI.FI MCP server. It runs a server
+// in-process behind an MCP client, with the LI.FI API replaced by a mock, so tools can
+// be called end to end from Go tests:
+//
+//	func TestQuote(t *testing.T) {
+//		h := lifitest.New(t, server.Options{})
+//		h.API.HandleJSON("/v1/quote", http.StatusOK, quoteFixture)
+//		var quote map[string]interface{}
+//		h.CallToolJSON("get-quote", map[string]interface{}{...}, &quote)
+//	}
+//
+// RPC-backed tools need a node; RequireRPC points them at a local one such as Anvil.
+package lifitest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lifinance/lifi-mcp/server"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RPCURLEnv names the environment variable holding the RPC URL of a local node
+// (e.g. Anvil forking mainnet) for tests that need one
+const RPCURLEnv = "LIFI_MCP_TEST_RPC_URL"
+
+// Harness is a server under test with its mock LI.FI API and a connected MCP client
+type Harness struct {
+	Server *server.Server
+	Client *client.Client
+	API    *MockAPI
+
+	// APIKey, when set, is passed with every call as the caller's LI.FI API key
+	APIKey string
+
+	t testing.TB
+}
+
+// New starts a server with opts, wired to a fresh MockAPI, and connects an in-process
+// MCP client to it. Logs are discarded unless opts.Logger is set, and the background
+// chain refresh is off unless opts.ChainsCacheTTL is set. Everything is shut down when
+// the test ends.
+func New(t testing.TB, opts server.Options) *Harness {
+	t.Helper()
+
+	api := NewMockAPI()
+	t.Cleanup(api.Close)
+
+	opts.APIHTTPClient = api.Client()
+	if opts.Version == "" {
+		opts.Version = "test"
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if opts.ChainsCacheTTL == 0 {
+		opts.ChainsCacheTTL = -1
+	}
+	s := server.New(opts)
+	t.Cleanup(s.Close)
+
+	c, err := client.NewInProcessClient(s.GetMCPServer())
+	if err != nil {
+		t.Fatalf("lifitest: failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("lifitest: failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "lifitest", Version: opts.Version}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("lifitest: failed to initialize client: %v", err)
+	}
+
+	return &Harness{Server: s, Client: c, API: api, t: t}
+}
+
+// RequireRPC returns the RPC URL of the local test node from LIFI_MCP_TEST_RPC_URL,
+// skipping the test when it is not set
+func RequireRPC(t testing.TB) string {
+	t.Helper()
+	rpcURL := os.Getenv(RPCURLEnv)
+	if rpcURL == "" {
+		t.Skipf("%s is not set; start a node (e.g. anvil --fork-url ...) and point it there", RPCURLEnv)
+	}
+	return rpcURL
+}
+
+// CallTool calls a tool through the MCP client and returns its result, failing the
+// test on protocol errors. Tool errors are returned as results with IsError set.
+func (h *Harness) CallTool(name string, args map[string]interface{}) *mcp.CallToolResult {
+	h.t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	ctx := server.StaticAPIKey(h.APIKey)(context.Background())
+	result, err := h.Client.CallTool(ctx, request)
+	if err != nil {
+		h.t.Fatalf("lifitest: %s: %v", name, err)
+	}
+	return result
+}
+
+// CallToolJSON calls a tool that must succeed and decodes its JSON result into out
+func (h *Harness) CallToolJSON(name string, args map[string]interface{}, out interface{}) {
+	h.t.Helper()
+	result := h.CallTool(name, args)
+	text := ResultText(result)
+	if result.IsError {
+		h.t.Fatalf("lifitest: %s returned a tool error: %s", name, text)
+	}
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		h.t.Fatalf("lifitest: %s returned invalid JSON: %v\n%s", name, err, text)
+	}
+}
+
+// ToolError calls a tool that must fail and returns its error message
+func (h *Harness) ToolError(name string, args map[string]interface{}) string {
+	h.t.Helper()
+	result := h.CallTool(name, args)
+	if !result.IsError {
+		h.t.Fatalf("lifitest: %s succeeded, expected a tool error: %s", name, ResultText(result))
+	}
+	return ResultText(result)
+}
+
+// AssertToolSchemas lists the tools through the MCP client and checks that every one
+// has a description, an annotation title and an object input schema whose required
+// parameters are all declared
+func (h *Harness) AssertToolSchemas() {
+	h.t.Helper()
+	tools, err := h.Client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		h.t.Fatalf("lifitest: failed to list tools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if strings.TrimSpace(tool.Description) == "" {
+			h.t.Errorf("tool %s: missing description", tool.Name)
+		}
+		if tool.Annotations.Title == "" {
+			h.t.Errorf("tool %s: missing annotation title", tool.Name)
+		}
+		if tool.InputSchema.Type != "object" {
+			h.t.Errorf("tool %s: input schema type is %q, want \"object\"", tool.Name, tool.InputSchema.Type)
+		}
+		for _, name := range tool.InputSchema.Required {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				h.t.Errorf("tool %s: required parameter %s is not declared", tool.Name, name)
+			}
+		}
+		for name, prop := range tool.InputSchema.Properties {
+			schema, ok := prop.(map[string]interface{})
+			if !ok || schema["type"] == nil {
+				h.t.Errorf("tool %s: parameter %s has no type", tool.Name, name)
+			}
+		}
+	}
+}
+
+// ResultText returns the concatenated text content of a tool result
+func ResultText(result *mcp.CallToolResult) string {
+	var text strings.Builder
+	for _, c := range result.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			text.WriteString(tc.Text)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,354 @@
+package lifitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/lifinance/lifi-mcp/lifitest"
+	"github.com/lifinance/lifi-mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	native  = "0x0000000000000000000000000000000000000000"
+	usdc    = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	arbUSDC = "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"
+	wallet  = "0x1111111111111111111111111111111111111111"
+	txHash  = "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+	// Mainnet contracts for the RPC-backed tools, which expect a node forking mainnet
+	bayc        = "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"
+	vitalik     = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+	lifiDiamond = "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE"
+
+	// anvilAccount is the first prefunded, unlocked Anvil account
+	anvilAccount = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+	// solanaWallet is a valid base58 Solana address
+	solanaWallet = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	solanaUSDC   = "EPjFWdd5AufqSZqDUqLqkAhmVMwr5BvhWqyHf6vDqxtY"
+)
+
+// toolCase is one tool call that must succeed
+type toolCase struct {
+	tool string
+	args map[string]interface{}
+}
+
+var (
+	ethToken  = map[string]interface{}{"address": native, "chainId": 1, "symbol": "ETH", "decimals": 18, "name": "ETH", "priceUSD": "2500"}
+	usdcToken = map[string]interface{}{"address": usdc, "chainId": 1, "symbol": "USDC", "decimals": 6, "name": "USD Coin", "priceUSD": "1"}
+	arbToken  = map[string]interface{}{"address": arbUSDC, "chainId": 42161, "symbol": "USDC", "decimals": 6, "name": "USD Coin", "priceUSD": "1"}
+
+	quoteFixture = map[string]interface{}{
+		"id":          "quote-1",
+		"type":        "lifi",
+		"tool":        "stargate",
+		"toolDetails": map[string]interface{}{"key": "stargate", "name": "Stargate"},
+		"action": map[string]interface{}{
+			"fromChainId": 1, "toChainId": 42161,
+			"fromToken": usdcToken, "toToken": arbToken,
+			"fromAmount": "1000000000", "fromAddress": wallet, "toAddress": wallet,
+		},
+		"estimate": map[string]interface{}{
+			"fromAmount": "1000000000", "toAmount": "999000000", "toAmountMin": "994000000",
+			"fromAmountUSD": "1000", "toAmountUSD": "999", "executionDuration": 60,
+			"approvalAddress": lifiDiamond,
+			"feeCosts":        []interface{}{map[string]interface{}{"amountUSD": "0.5", "included": true}},
+			"gasCosts":        []interface{}{map[string]interface{}{"amountUSD": "1.2"}},
+		},
+		"includedSteps": []interface{}{},
+	}
+
+	transferFixture = map[string]interface{}{
+		"tool":      "stargate",
+		"status":    "PENDING",
+		"sending":   map[string]interface{}{"txHash": txHash, "chainId": 1, "timestamp": 1700000000},
+		"receiving": map[string]interface{}{"chainId": 42161},
+	}
+)
+
+// newHarness starts a harness whose mock API answers every endpoint the tools use
+func newHarness(t *testing.T, opts server.Options) *lifitest.Harness {
+	t.Helper()
+	h := lifitest.New(t, opts)
+	api := h.API
+
+	api.HandleJSON("/v1/tokens", http.StatusOK, map[string]interface{}{"tokens": map[string]interface{}{
+		"1":     []interface{}{ethToken, usdcToken},
+		"42161": []interface{}{arbToken},
+	}})
+	api.HandleJSON("/v1/token", http.StatusOK, usdcToken)
+	api.HandleJSON("/v1/tools", http.StatusOK, map[string]interface{}{
+		"bridges": []interface{}{map[string]interface{}{
+			"key": "stargate", "name": "Stargate",
+			"supportedChains": []interface{}{map[string]interface{}{"fromChainId": 1, "toChainId": 42161}},
+		}},
+		"exchanges": []interface{}{map[string]interface{}{"key": "1inch", "name": "1inch", "supportedChains": []interface{}{1, 42161}}},
+	})
+	api.HandleJSON("/v1/connections", http.StatusOK, map[string]interface{}{"connections": []interface{}{map[string]interface{}{
+		"fromChainId": 1, "toChainId": 42161,
+		"fromTokens": []interface{}{usdcToken}, "toTokens": []interface{}{arbToken},
+	}}})
+	api.HandleJSON("/v1/quote", http.StatusOK, quoteFixture)
+	api.HandleJSON("/v1/quote/toAmount", http.StatusOK, quoteFixture)
+	api.HandleJSON("/v1/quote/contractCalls", http.StatusOK, quoteFixture)
+	api.HandleJSON("/v1/advanced/routes", http.StatusOK, map[string]interface{}{"routes": []interface{}{map[string]interface{}{
+		"id": "route-1", "fromChainId": 1, "toChainId": 42161, "fromAmount": "1000000000", "toAmount": "999000000",
+		"steps": []interface{}{quoteFixture},
+	}}})
+	api.HandleJSON("/v1/advanced/stepTransaction", http.StatusOK, quoteFixture)
+	api.HandleJSON("/v1/status", http.StatusOK, map[string]interface{}{
+		"status": "DONE", "substatus": "COMPLETED", "tool": "stargate",
+		"sending":   map[string]interface{}{"txHash": txHash, "chainId": 1},
+		"receiving": map[string]interface{}{"chainId": 42161},
+	})
+	api.HandleJSON("/v1/analytics/transfers", http.StatusOK, map[string]interface{}{"transfers": []interface{}{transferFixture}})
+	api.HandleJSON("/v1/gas/prices", http.StatusOK, map[string]interface{}{"1": map[string]interface{}{"standard": 20, "fast": 30, "fastest": 40}})
+	api.HandleJSON("/v1/gas/suggestion/42161", http.StatusOK, map[string]interface{}{"available": true, "recommended": map[string]interface{}{"amount": "1000000000000000", "amountUsd": "2.5"}})
+	api.HandleJSON("/v1/keys/test", http.StatusOK, map[string]interface{}{})
+	return h
+}
+
+// newSolanaRPC starts a Solana JSON-RPC stand-in answering getBalance and
+// getTokenAccountsByOwner
+func newSolanaRPC(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{}
+		switch req.Method {
+		case "getBalance":
+			result = map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": 1500000000}
+		case "getTokenAccountsByOwner":
+			result = map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": []interface{}{map[string]interface{}{
+				"pubkey": solanaWallet,
+				"account": map[string]interface{}{"data": map[string]interface{}{"parsed": map[string]interface{}{"info": map[string]interface{}{
+					"mint":        solanaUSDC,
+					"tokenAmount": map[string]interface{}{"amount": "2500000", "decimals": 6},
+				}}}},
+			}}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// apiToolCases are the calls served by the mock LI.FI API alone
+func apiToolCases(solanaRPC string) []toolCase {
+	quoteArgs := func(extra map[string]interface{}) map[string]interface{} {
+		args := map[string]interface{}{
+			"fromChain": "1", "toChain": "42161", "fromToken": usdc, "toToken": arbUSDC,
+			"fromAddress": wallet, "fromAmount": "1000000000",
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+	return []toolCase{
+		{"health-check", nil},
+		{"get-tokens", map[string]interface{}{"chains": "1"}},
+		{"search-tokens", map[string]interface{}{"query": "USDC"}},
+		{"get-token", map[string]interface{}{"chain": "1", "token": usdc}},
+		{"get-token-prices", map[string]interface{}{"tokens": []interface{}{map[string]interface{}{"chain": "1", "address": usdc}}}},
+		{"convert-to-usd", map[string]interface{}{"chain": "1", "token": usdc, "amount": "12.5"}},
+		{"format-token-amount", map[string]interface{}{"amount": "1500000", "decimals": 6}},
+		{"parse-token-amount", map[string]interface{}{"amount": "1.5", "chain": "1", "token": usdc}},
+		{"get-quote", quoteArgs(nil)},
+		{"get-quote-to-amount", map[string]interface{}{
+			"fromChain": "1", "toChain": "42161", "fromToken": usdc, "toToken": arbUSDC,
+			"fromAddress": wallet, "toAmount": "999000000",
+		}},
+		{"get-status", map[string]interface{}{"txHash": txHash}},
+		{"track-transfer", map[string]interface{}{"txHash": txHash, "timeoutSeconds": 5}},
+		{"get-transfers", map[string]interface{}{"wallet": wallet}},
+		{"find-pending-bridges", map[string]interface{}{"wallet": wallet}},
+		{"get-chains", nil},
+		{"get-connections", map[string]interface{}{"fromChain": "1", "toChain": "42161"}},
+		{"get-tools", nil},
+		{"check-route-feasibility", map[string]interface{}{"fromChain": "1", "toChain": "42161", "fromToken": usdc, "toToken": arbUSDC}},
+		{"compare-quotes", quoteArgs(nil)},
+		{"analyze-price-impact", quoteArgs(nil)},
+		{"get-routes", map[string]interface{}{
+			"fromChainId": "1", "toChainId": "42161", "fromTokenAddress": usdc, "toTokenAddress": arbUSDC,
+			"fromAddress": wallet, "fromAmount": "1000000000",
+		}},
+		{"get-quote-with-calls", quoteArgs(map[string]interface{}{"contractCalls": []interface{}{map[string]interface{}{
+			"toContractAddress": lifiDiamond, "toContractCallData": "0x", "toContractGasLimit": "100000",
+		}}})},
+		{"get-step-transaction", map[string]interface{}{"step": quoteFixture}},
+		{"get-gas-prices", nil},
+		{"get-gas-suggestion", map[string]interface{}{"chainId": "42161"}},
+		{"test-api-key", nil},
+		{"get-rate-limit-status", nil},
+		{"get-chain-by-id", map[string]interface{}{"id": "1"}},
+		{"get-chain-by-name", map[string]interface{}{"name": "ethereum"}},
+		{"plan-approval", map[string]interface{}{"quote": map[string]interface{}{
+			"action":   map[string]interface{}{"fromChainId": 1, "fromAddress": wallet, "fromAmount": "1000000000000000000", "fromToken": ethToken},
+			"estimate": map[string]interface{}{"approvalAddress": lifiDiamond},
+		}}},
+		{"decode-calldata", map[string]interface{}{
+			"data":            "0xa9059cbb000000000000000000000000111111111111111111111111111111111111111100000000000000000000000000000000000000000000000000000000000f4240",
+			"lookupSignature": false,
+		}},
+		{"get-solana-balance", map[string]interface{}{"address": solanaWallet, "rpcUrl": solanaRPC}},
+		{"get-spl-token-balance", map[string]interface{}{"mintAddress": solanaUSDC, "walletAddress": solanaWallet, "rpcUrl": solanaRPC}},
+	}
+}
+
+// rpcToolCases are the calls that need an EVM node forking mainnet. txHash is a mined
+// transaction on it and nftOwner the owner of BAYC #1.
+func rpcToolCases(rpcURL, txHash, nftOwner string) []toolCase {
+	return []toolCase{
+		{"get-gas-price", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL}},
+		{"get-block", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL}},
+		{"get-chain-head", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL}},
+		{"get-native-token-balance", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "address": vitalik}},
+		{"get-token-balance", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "tokenAddress": usdc, "walletAddress": vitalik}},
+		{"get-token-balances", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "tokenAddresses": []interface{}{usdc}, "walletAddress": vitalik}},
+		{"get-wallet-portfolio", map[string]interface{}{"address": vitalik, "chains": "1"}},
+		{"get-allowance", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "tokenAddress": usdc, "ownerAddress": vitalik, "spenderAddress": lifiDiamond}},
+		{"get-token-approvals", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "owner": vitalik, "tokens": usdc, "spenders": lifiDiamond}},
+		{"revoke-approval", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "owner": vitalik, "tokenAddress": usdc, "spenderAddress": lifiDiamond}},
+		{"get-nft-balance", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "contractAddress": bayc, "ownerAddress": vitalik}},
+		{"get-nft-owner", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "contractAddress": bayc, "tokenId": "1"}},
+		{"transfer-nft", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "contractAddress": bayc, "fromAddress": nftOwner, "toAddress": wallet, "tokenId": "1"}},
+		{"wait-for-receipt", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "txHash": txHash, "timeoutSeconds": 30}},
+		{"get-transaction", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "txHash": txHash}},
+		{"get-receipt", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "txHash": txHash}},
+		{"simulate-transaction", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "transactionRequest": map[string]interface{}{
+			"from": anvilAccount, "to": wallet, "value": "0x1", "data": "0x",
+		}}},
+	}
+}
+
+func runToolCases(t *testing.T, opts server.Options, cases []toolCase) {
+	for _, c := range cases {
+		t.Run(c.tool, func(t *testing.T) {
+			h := newHarness(t, opts)
+			h.APIKey = "test-key"
+			result := h.CallTool(c.tool, c.args)
+			if result.IsError {
+				t.Fatalf("%s returned a tool error: %s", c.tool, lifitest.ResultText(result))
+			}
+			if lifitest.ResultText(result) == "" {
+				t.Fatalf("%s returned no text content", c.tool)
+			}
+		})
+	}
+}
+
+func TestToolSchemas(t *testing.T) {
+	lifitest.New(t, server.Options{}).AssertToolSchemas()
+}
+
+// TestEveryToolCovered keeps the cases in step with the registered tools
+func TestEveryToolCovered(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, c := range append(apiToolCases(""), rpcToolCases("", "", "")...) {
+		covered[c.tool] = true
+	}
+
+	h := lifitest.New(t, server.Options{})
+	tools, err := h.Client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if !covered[tool.Name] {
+			t.Errorf("tool %s has no test case", tool.Name)
+		}
+	}
+}
+
+func TestAPITools(t *testing.T) {
+	runToolCases(t, server.Options{}, apiToolCases(newSolanaRPC(t)))
+}
+
+func TestRPCTools(t *testing.T) {
+	rpcURL := lifitest.RequireRPC(t)
+
+	client, err := rpc.Dial(rpcURL)
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", rpcURL, err)
+	}
+	defer client.Close()
+
+	// A fresh transaction for the transaction tools, sent from Anvil's unlocked account
+	var hash string
+	if err := client.Call(&hash, "eth_sendTransaction", map[string]interface{}{
+		"from": anvilAccount, "to": wallet, "value": "0x1",
+	}); err != nil {
+		t.Fatalf("failed to send a test transaction: %v", err)
+	}
+
+	// transfer-nft checks that fromAddress owns the token
+	h := newHarness(t, server.Options{})
+	var owner struct {
+		Owner string `json:"owner"`
+	}
+	h.CallToolJSON("get-nft-owner", map[string]interface{}{"chain": "1", "rpcUrl": rpcURL, "contractAddress": bayc, "tokenId": "1"}, &owner)
+
+	opts := server.Options{RPCOverrides: map[int]string{1: rpcURL}}
+	runToolCases(t, opts, rpcToolCases(rpcURL, hash, owner.Owner))
+}
+
+func TestToolErrors(t *testing.T) {
+	h := newHarness(t, server.Options{})
+	h.API.HandleJSON("/v1/quote", http.StatusNotFound, map[string]interface{}{"message": "No available quotes for the requested transfer"})
+
+	msg := h.ToolError("get-quote", map[string]interface{}{
+		"fromChain": "1", "toChain": "42161", "fromToken": usdc, "toToken": arbUSDC,
+		"fromAddress": wallet, "fromAmount": "1000000000",
+	})
+	if msg == "" {
+		t.Fatal("expected an error message")
+	}
+
+	requests := h.API.Requests()
+	last := requests[len(requests)-1]
+	if last.Path != "/v1/quote" || last.Query.Get("fromAmount") != "1000000000" {
+		t.Fatalf("unexpected last request: %s %v", last.Path, last.Query)
+	}
+	if key := last.Header.Get("x-lifi-api-key"); key != "" {
+		t.Fatalf("anonymous call sent API key %q", key)
+	}
+}
+
+func TestQuoteAmountHuman(t *testing.T) {
+	tests := []struct {
+		tool, human, amountParam, path, want string
+	}{
+		{"get-quote", "1000", "fromAmount", "/v1/quote", "1000000000"},
+		{"get-quote-to-amount", "999", "toAmount", "/v1/quote/toAmount", "999000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			h := newHarness(t, server.Options{})
+			result := h.CallTool(tt.tool, map[string]interface{}{
+				"fromChain": "1", "toChain": "42161", "fromToken": usdc, "toToken": arbUSDC,
+				"fromAddress": wallet, "amountHuman": tt.human,
+			})
+			if result.IsError {
+				t.Fatalf("%s returned a tool error: %s", tt.tool, lifitest.ResultText(result))
+			}
+
+			requests := h.API.Requests()
+			last := requests[len(requests)-1]
+			if last.Path != tt.path || last.Query.Get(tt.amountParam) != tt.want {
+				t.Fatalf("unexpected last request: %s %v", last.Path, last.Query)
+			}
+		})
+	}
+}
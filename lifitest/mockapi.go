@@ -0,0 +1,134 @@
+package lifitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/lifinance/lifi-mcp/server"
+)
+
+// RecordedRequest is a request received by the mock LI.FI API
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// MockAPI is a stand-in for the LI.FI API. Responses are registered per path; paths
+// without one answer 404 like an unknown LI.FI endpoint. /v1/chains, /v1/tokens and
+// /v1/tools have default fixtures so tools that load reference data work out of the box.
+type MockAPI struct {
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]http.HandlerFunc
+	requests []RecordedRequest
+}
+
+// NewMockAPI starts a mock LI.FI API with the default fixtures
+func NewMockAPI() *MockAPI {
+	m := &MockAPI{routes: map[string]http.HandlerFunc{}}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	m.URL = m.server.URL
+
+	m.HandleJSON("/v1/chains", http.StatusOK, DefaultChains())
+	m.HandleJSON("/v1/tokens", http.StatusOK, map[string]interface{}{"tokens": map[string]interface{}{}})
+	m.HandleJSON("/v1/tools", http.StatusOK, map[string]interface{}{"bridges": []interface{}{}, "exchanges": []interface{}{}})
+	return m
+}
+
+// DefaultChains is the /v1/chains fixture: Ethereum and Arbitrum without public RPCs,
+// so RPC-backed tools need an rpcUrl argument or an RPC override
+func DefaultChains() server.ChainData {
+	eth := server.Token{Address: "0x0000000000000000000000000000000000000000", Symbol: "ETH", Decimals: 18, Name: "ETH"}
+	return server.ChainData{Chains: []server.Chain{
+		{ID: 1, Key: "eth", Name: "Ethereum", ChainType: "EVM", NativeToken: eth},
+		{ID: 42161, Key: "arb", Name: "Arbitrum", ChainType: "EVM", NativeToken: eth},
+	}}
+}
+
+// Handle registers the handler for an API path such as "/v1/quote", replacing any
+// earlier one
+func (m *MockAPI) Handle(path string, handler http.HandlerFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[path] = handler
+}
+
+// HandleJSON registers a fixed JSON response for an API path
+func (m *MockAPI) HandleJSON(path string, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("lifitest: cannot serialize fixture for %s: %v", path, err))
+	}
+	m.Handle(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(data)
+	})
+}
+
+// Requests returns the requests received so far, oldest first
+func (m *MockAPI) Requests() []RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RecordedRequest(nil), m.requests...)
+}
+
+// Client returns an http.Client that sends every request to the mock, whatever its
+// host, for use as the server's LI.FI API client
+func (m *MockAPI) Client() *http.Client {
+	target, _ := url.Parse(m.URL)
+	return &http.Client{Transport: rewriteTransport{target: target, next: m.server.Client().Transport}}
+}
+
+// Close shuts the mock down
+func (m *MockAPI) Close() {
+	m.server.Close()
+}
+
+func (m *MockAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	handler, ok := m.routes[r.URL.Path]
+	m.mu.Unlock()
+
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"message":"lifitest: no mock response for %s"}`, r.URL.Path)
+		return
+	}
+	handler(w, r)
+}
+
+// rewriteTransport redirects requests to the mock server's scheme and host
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return t.next.RoundTrip(r)
+}
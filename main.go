@@ -21,14 +21,47 @@ func main() {
 	var (
 		port        = flag.Int("port", 8080, "HTTP server port")
 		host        = flag.String("host", "0.0.0.0", "HTTP server host (use 0.0.0.0 for container deployment)")
-		transport   = flag.String("transport", "stdio", "Transport mode: stdio or http")
+		transport   = flag.String("transport", "stdio", "Transport mode: stdio, http or sse")
+		httpAddr    = flag.String("http", "", "Serve Streamable HTTP on this address (e.g. ':8080'); implies --transport http")
+		sseAddr     = flag.String("sse", "", "Serve legacy SSE on this address (e.g. ':8080'); implies --transport sse")
+		sseBasePath = flag.String("sse-base-path", "", "Base path for the SSE and message endpoints (e.g. '/lifi')")
+		apiKey      = flag.String("api-key", "", "Default LI.FI API key, used when a request carries none (defaults to $LIFI_API_KEY for stdio only)")
+		rateLimit   = flag.Int("rate-limit", 0, "Maximum LI.FI API requests per --rate-period (default: 200, per minute with an API key or per 2 hours without)")
+		ratePeriod  = flag.Duration("rate-period", 0, "Rate limit period, e.g. 1m or 2h (default depends on whether an API key is set)")
+		chainsTTL   = flag.Duration("chains-cache-ttl", server.DefaultChainsCacheTTL, "How long chain data is cached before it is refreshed (0 disables expiry)")
+		tokenCache  = flag.String("token-cache-file", "", "Persist token metadata to this JSON file across restarts (default: in-memory only)")
+		configPath  = flag.String("config", defaultConfigPath(), "Path to a YAML config file whose keys are flag names")
 		showVersion = flag.Bool("version", false, "Show version information")
 		logLevel    = flag.String("log-level", "info", "Log level: debug, info, warn, error")
+		logFormat   = flag.String("log-format", "json", "Log format: json or text")
+		maxFeePct   = flag.Float64("max-fee-percent", server.DefaultMaxFeePercent, "Flag quotes whose fees and gas exceed this percentage of the transferred USD value (0 disables)")
+		checksum    = flag.String("address-checksum", "mixed", "EIP-55 checksum enforcement for address arguments: off, mixed (reject bad mixed-case checksums) or strict (require checksums)")
 	)
+	rpcOverrides := rpcOverridesFlag{}
+	flag.Var(rpcOverrides, "rpc", "Preferred RPC URL for a chain as chainId=url (repeatable, e.g. --rpc 1=https://eth-mainnet.example/KEY)")
+	var enableTools, disableTools toolPatternsFlag
+	flag.Var(&enableTools, "enable-tools", "Only expose tools matching these comma-separated name patterns (e.g. \"get-*,search-tokens\"; default: all)")
+	flag.Var(&disableTools, "disable-tools", "Hide tools matching these comma-separated name patterns (e.g. \"transfer-nft,revoke-approval\")")
+	var toolLimits toolLimitsFlag
+	var toolTimeouts toolTimeoutsFlag
+	flag.Var(&toolTimeouts, "tool-timeout", "Timeout for tools matching a pattern as pattern=duration (repeatable, e.g. --tool-timeout \"get-*=15s\"; \"*\" sets the default of 1m, 0 disables)")
+	flag.Var(&toolLimits, "tool-limit", "Per-session call budget as pattern=calls/period (repeatable, e.g. --tool-limit \"get-quote*=100/1h\")")
 	flag.Parse()
 
+	// Flags take precedence over LIFI_MCP_* environment variables, which take
+	// precedence over the config file
+	configSet := false
+	flag.Visit(func(f *flag.Flag) {
+		configSet = configSet || f.Name == "config"
+	})
+	fromAlias, err := applyConfig(flag.CommandLine, *configPath, configSet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Initialize structured logging
-	logger := initLogger(*logLevel)
+	logger := initLogger(*logLevel, *logFormat)
 	slog.SetDefault(logger)
 
 	if *showVersion {
@@ -36,30 +69,87 @@ func main() {
 		return
 	}
 
-	// Create the server (no API key - it's per-request now)
-	s := server.NewServer(version, logger)
+	checksumMode, err := server.ParseChecksumMode(*checksum)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// --http and --sse override both the transport and the listen address
+	if *httpAddr != "" && *sseAddr != "" {
+		fmt.Fprintln(os.Stderr, "Error: --http and --sse are mutually exclusive")
+		os.Exit(1)
+	}
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	if *httpAddr != "" {
+		*transport = "http"
+		addr = *httpAddr
+	}
+	if *sseAddr != "" {
+		*transport = "sse"
+		addr = *sseAddr
+	}
+
+	// LIFI_API_KEY is the user's own key when running over stdio. HTTP and SSE only fall
+	// back to a key configured for the server itself, so a key that happens to be in the
+	// environment isn't spent on behalf of every unauthenticated client.
+	defaultAPIKey := *apiKey
+	if *transport != "stdio" && fromAlias["api-key"] {
+		logger.Warn("Ignoring LIFI_API_KEY for requests without an API key; pass --api-key to use it as the server's default key")
+		defaultAPIKey = ""
+	}
+
+	// Raise the client-side rate limit when a default API key is configured
+	limit, period := server.DefaultRateLimit(defaultAPIKey != "")
+	if *rateLimit > 0 {
+		limit = *rateLimit
+	}
+	if *ratePeriod > 0 {
+		period = *ratePeriod
+	}
+	if period < time.Duration(limit) {
+		fmt.Fprintf(os.Stderr, "Error: --rate-period %v is too short for --rate-limit %d\n", period, limit)
+		os.Exit(1)
+	}
+
+	// Create the server (API keys are resolved per-request)
+	s := server.NewServer(version, logger,
+		server.WithRateLimit(limit, period),
+		server.WithChainsCacheTTL(*chainsTTL),
+		server.WithTokenCacheFile(*tokenCache),
+		server.WithRPCOverrides(rpcOverrides),
+		server.WithToolFilter(enableTools, disableTools),
+		server.WithToolLimits(toolLimits),
+		server.WithToolTimeouts(toolTimeouts),
+		server.WithAddressChecksum(checksumMode),
+		server.WithMaxFeePercent(*maxFeePct),
+	)
+	defer s.Close()
+
+	if *transport != "stdio" && defaultAPIKey != "" {
+		logger.Warn("Requests without an API key header will use the server's default API key: anyone who can reach this server spends its quota",
+			"transport", *transport,
+		)
+	}
 
 	switch *transport {
 	case "stdio":
-		hasKey := os.Getenv("LIFI_API_KEY") != ""
 		logger.Info("Starting LiFi MCP Server (stdio)",
 			"version", version,
-			"apiKeySet", hasKey,
+			"apiKeySet", *apiKey != "",
 		)
 		if err := mcpserver.ServeStdio(
 			s.GetMCPServer(),
-			mcpserver.WithStdioContextFunc(server.ExtractAPIKeyFromEnv),
+			mcpserver.WithStdioContextFunc(server.StaticAPIKey(*apiKey)),
 		); err != nil {
 			logger.Error("Stdio server error", "error", err)
 			os.Exit(1)
 		}
 
 	case "http":
-		logger.Info("API keys are now passed per-request via Authorization header (Bearer token) or X-LiFi-Api-Key header")
-
-		// Set up signal handling for graceful shutdown
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		logger.Info("API keys are now passed per-request via Authorization header (Bearer token) or X-LiFi-Api-Key header",
+			"defaultApiKeySet", defaultAPIKey != "",
+		)
 
 		// Create the Streamable HTTP server
 		httpServer := mcpserver.NewStreamableHTTPServer(
@@ -67,43 +157,73 @@ func main() {
 			mcpserver.WithEndpointPath("/mcp"),
 			mcpserver.WithHeartbeatInterval(30*time.Second),
 			mcpserver.WithStateLess(true), // Stateless for multi-tenant
-			mcpserver.WithHTTPContextFunc(server.ExtractAPIKeyFromRequest),
+			mcpserver.WithHTTPContextFunc(server.WithDefaultAPIKey(defaultAPIKey)),
 		)
 
-		// Start server in a goroutine
-		addr := fmt.Sprintf("%s:%d", *host, *port)
-		go func() {
-			logger.Info("Starting LiFi MCP Server",
-				"version", version,
-				"address", addr,
-				"endpoint", "/mcp",
-			)
-			if err := httpServer.Start(addr); err != nil {
-				logger.Error("Server error", "error", err)
-				os.Exit(1)
-			}
-		}()
-
-		// Wait for shutdown signal
-		<-sigChan
-		logger.Info("Received shutdown signal, exiting...")
-
-		// Graceful shutdown
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err := httpServer.Shutdown(ctx); err != nil {
-			logger.Error("Error during shutdown", "error", err)
-		}
+		serveUntilSignal(logger, httpServer, addr, "/mcp")
+
+	case "sse":
+		logger.Info("API keys are passed per-request via Authorization header (Bearer token) or X-LiFi-Api-Key header",
+			"defaultApiKeySet", defaultAPIKey != "",
+		)
+
+		// Create the SSE server for clients that do not speak Streamable HTTP
+		sseServer := mcpserver.NewSSEServer(
+			s.GetMCPServer(),
+			mcpserver.WithStaticBasePath(*sseBasePath),
+			mcpserver.WithKeepAlive(true),
+			mcpserver.WithSSEContextFunc(server.WithDefaultAPIKey(defaultAPIKey)),
+		)
+
+		serveUntilSignal(logger, sseServer, addr, sseServer.CompleteSsePath())
 
 	default:
 		logger.Error("Unknown transport mode", "transport", *transport)
-		fmt.Fprintf(os.Stderr, "Error: unknown transport %q (use \"http\" or \"stdio\")\n", *transport)
+		fmt.Fprintf(os.Stderr, "Error: unknown transport %q (use \"stdio\", \"http\" or \"sse\")\n", *transport)
 		os.Exit(1)
 	}
 }
 
-// initLogger creates a structured logger with the specified level
-func initLogger(level string) *slog.Logger {
+// networkServer is the common surface of the Streamable HTTP and SSE servers
+type networkServer interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+// serveUntilSignal starts a network transport and blocks until SIGINT/SIGTERM,
+// then shuts it down gracefully
+func serveUntilSignal(logger *slog.Logger, srv networkServer, addr, endpoint string) {
+	// Set up signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	// Start server in a goroutine
+	go func() {
+		logger.Info("Starting LiFi MCP Server",
+			"version", version,
+			"address", addr,
+			"endpoint", endpoint,
+		)
+		if err := srv.Start(addr); err != nil {
+			logger.Error("Server error", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	// Wait for shutdown signal
+	<-sigChan
+	logger.Info("Received shutdown signal, exiting...")
+
+	// Graceful shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("Error during shutdown", "error", err)
+	}
+}
+
+// initLogger creates a structured logger with the specified level and format
+func initLogger(level, format string) *slog.Logger {
 	var logLevel slog.Level
 	switch strings.ToLower(level) {
 	case "debug":
@@ -122,7 +242,14 @@ func initLogger(level string) *slog.Logger {
 		Level: logLevel,
 	}
 
-	// Use JSON handler for structured logging (easier to parse in production)
-	handler := slog.NewJSONHandler(os.Stderr, opts)
+	// Default to JSON for structured logging (easier to parse in production);
+	// text is easier to read when debugging locally
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	default:
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	}
 	return slog.New(handler)
 }
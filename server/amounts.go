@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxTokenDecimals bounds the decimals argument; ERC20 decimals is a uint8
+const maxTokenDecimals = 255
+
+// tokenDecimals is the resolved decimals of a token and where they came from
+type tokenDecimals struct {
+	Decimals int
+	Symbol   string
+	Source   string
+}
+
+// resolveTokenDecimals returns the decimals argument if given, otherwise looks them up
+// from the chain and token arguments
+func (s *Server) resolveTokenDecimals(ctx context.Context, request mcp.CallToolRequest, apiKey string) (*tokenDecimals, error) {
+	decimals := mcp.ParseInt(request, "decimals", -1)
+	if decimals >= 0 {
+		if decimals > maxTokenDecimals {
+			return nil, fmt.Errorf("decimals must be between 0 and %d", maxTokenDecimals)
+		}
+		return &tokenDecimals{Decimals: decimals, Source: "provided"}, nil
+	}
+
+	chain := getStringArg(request, "chain")
+	token := getStringArg(request, "token")
+	if chain == "" || token == "" {
+		return nil, fmt.Errorf("either decimals or both chain and token are required")
+	}
+
+	return s.lookupTokenDecimals(ctx, chain, token, getStringArg(request, "rpcUrl"), apiKey)
+}
+
+// lookupTokenDecimals looks a token up in the LI.FI token API, falling back to reading
+// decimals() on-chain for tokens LI.FI does not know
+func (s *Server) lookupTokenDecimals(ctx context.Context, chain, token, rpcUrl, apiKey string) (*tokenDecimals, error) {
+	entry, err := s.fetchToken(ctx, chain, token, apiKey)
+	if err == nil {
+		return &tokenDecimals{Decimals: entry.Decimals, Symbol: entry.Symbol, Source: "lifi"}, nil
+	}
+	s.logger.Debug("LI.FI token lookup failed, reading decimals on-chain", "chain", chain, "token", token, "error", err)
+
+	if err := ValidateAddress("token", token); err != nil {
+		return nil, err
+	}
+	rpcUrl, rpcErr := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if rpcErr != nil {
+		return nil, fmt.Errorf("token lookup failed (%v) and no RPC is available: %v", err, rpcErr)
+	}
+	client, release, rpcErr := s.rpcClients.get(ctx, rpcUrl)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	defer release()
+	chainID, rpcErr := client.ChainID(ctx)
+	if rpcErr != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %v", rpcErr)
+	}
+	metadata, rpcErr := s.tokenInfo(ctx, client, chainID, token)
+	if rpcErr != nil {
+		return nil, fmt.Errorf("failed to get token info for %s: %v", token, rpcErr)
+	}
+	if metadata.DecimalsDefaulted {
+		return nil, fmt.Errorf("token %s does not implement decimals(); pass decimals explicitly", token)
+	}
+	return &tokenDecimals{Decimals: metadata.Decimals, Symbol: metadata.Symbol, Source: "onchain"}, nil
+}
+
+func (s *Server) formatTokenAmountHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	amount := getStringArg(request, "amount")
+
+	if err := ValidateAmountAllowZero("amount", amount); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	token, err := s.resolveTokenDecimals(ctx, request, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	amountInt, _ := new(big.Int).SetString(amount, 10)
+	responseData := map[string]interface{}{
+		"amount":         amount,
+		"formatted":      formatUnits(amountInt, token.Decimals),
+		"decimals":       token.Decimals,
+		"decimalsSource": token.Source,
+	}
+	if token.Symbol != "" {
+		responseData["symbol"] = token.Symbol
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) parseTokenAmountHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	amountHuman := getStringArg(request, "amount")
+
+	token, err := s.resolveTokenDecimals(ctx, request, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	amount, err := parseUnits(amountHuman, token.Decimals)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("amount: %v", err)), nil
+	}
+
+	responseData := map[string]interface{}{
+		"amount":         amount.String(),
+		"formatted":      formatUnits(amount, token.Decimals),
+		"decimals":       token.Decimals,
+		"decimalsSource": token.Source,
+	}
+	if token.Symbol != "" {
+		responseData["symbol"] = token.Symbol
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
@@ -0,0 +1,590 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// LiFiDiamondAddress is the LI.FI Diamond contract, the spender of LI.FI swaps and bridges
+	// on most EVM chains
+	LiFiDiamondAddress = "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE"
+
+	// Permit2Address is Uniswap's Permit2 contract, a common spender of DEX approvals
+	Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+
+	// maxApprovalPairs caps the number of (token, spender) pairs checked in one call
+	maxApprovalPairs = 500
+
+	// maxApprovalLookbackBlocks caps how far back Approval logs are scanned
+	maxApprovalLookbackBlocks = 100000
+)
+
+var (
+	// approvalTopic is keccak256("Approval(address,address,uint256)")
+	approvalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+	// unlimitedAllowanceThreshold flags allowances of 2^128 or more as unlimited. Tokens
+	// that decrement an infinite (2^256-1) allowance on use stay far above it.
+	unlimitedAllowanceThreshold = new(big.Int).Lsh(big.NewInt(1), 128)
+
+	// defaultApprovalSpenders are checked when no spenders are given
+	defaultApprovalSpenders = []string{LiFiDiamondAddress, Permit2Address}
+)
+
+// tokenApproval is a non-zero ERC20 allowance granted by a wallet
+type tokenApproval struct {
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol,omitempty"`
+	Decimals  int    `json:"decimals"`
+	Spender   string `json:"spender"`
+	Allowance string `json:"allowance"`
+	Formatted string `json:"formatted"`
+	Unlimited bool   `json:"unlimited"`
+}
+
+// approvalPair is a (token, spender) combination whose allowance is checked
+type approvalPair struct {
+	token   common.Address
+	spender common.Address
+}
+
+// parseAddressList parses a comma-separated list of EVM addresses
+func parseAddressList(field, value string) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, a := range strings.Split(value, ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if err := ValidateAddress(field, a); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, common.HexToAddress(a))
+	}
+	return addresses, nil
+}
+
+func (s *Server) getTokenApprovalsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	owner := getStringArg(request, "owner")
+	tokensArg := getStringArg(request, "tokens")
+	spendersArg := getStringArg(request, "spenders")
+	lookbackBlocks := mcp.ParseInt(request, "lookbackBlocks", 0)
+
+	if err := ValidateAddress("owner", owner); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tokens, err := parseAddressList("tokens", tokensArg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if spendersArg == "" {
+		spendersArg = strings.Join(defaultApprovalSpenders, ",")
+	}
+	spenders, err := parseAddressList("spenders", spendersArg)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if lookbackBlocks < 0 || lookbackBlocks > maxApprovalLookbackBlocks {
+		return mcp.NewToolResultError(fmt.Sprintf("lookbackBlocks must be between 0 and %d", maxApprovalLookbackBlocks)), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	// Default to the major tokens LI.FI lists for the chain
+	known := map[common.Address]TokenListEntry{}
+	if len(tokens) == 0 {
+		tokensByChain, err := s.fetchTokenList(ctx, []string{chainID.String()}, apiKey)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		for _, t := range tokensByChain[chainID.String()] {
+			if strings.EqualFold(t.Address, ZeroAddress) {
+				continue
+			}
+			for _, symbol := range majorTokenSymbols {
+				if strings.EqualFold(t.Symbol, symbol) {
+					addr := common.HexToAddress(t.Address)
+					known[addr] = t
+					tokens = append(tokens, addr)
+					break
+				}
+			}
+		}
+	}
+
+	// Every requested token against every requested spender
+	ownerAddr := common.HexToAddress(owner)
+	seen := map[approvalPair]bool{}
+	var pairs []approvalPair
+	addPair := func(p approvalPair) {
+		if !seen[p] {
+			seen[p] = true
+			pairs = append(pairs, p)
+		}
+	}
+	for _, token := range tokens {
+		for _, spender := range spenders {
+			addPair(approvalPair{token: token, spender: spender})
+		}
+	}
+
+	// Discover further approvals from the owner's recent Approval logs
+	var scannedFrom uint64
+	if lookbackBlocks > 0 {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get block number: %v", err)), nil
+		}
+		if head > uint64(lookbackBlocks) {
+			scannedFrom = head - uint64(lookbackBlocks)
+		}
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(scannedFrom),
+			ToBlock:   new(big.Int).SetUint64(head),
+			Topics:    [][]common.Hash{{approvalTopic}, {common.BytesToHash(ownerAddr.Bytes())}},
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to scan Approval logs (try a smaller lookbackBlocks or an rpcUrl with a larger log range): %v", err)), nil
+		}
+		for _, l := range logs {
+			// ERC721 Approval events index the token ID as a third topic
+			if len(l.Topics) != 3 || len(l.Data) != 32 {
+				continue
+			}
+			addPair(approvalPair{token: l.Address, spender: common.BytesToAddress(l.Topics[2].Bytes())})
+		}
+	}
+
+	if len(pairs) > maxApprovalPairs {
+		return mcp.NewToolResultError(fmt.Sprintf("too many token/spender pairs to check (%d, max %d); narrow tokens, spenders or lookbackBlocks", len(pairs), maxApprovalPairs)), nil
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse ERC20 ABI: %v", err)), nil
+	}
+
+	calls := make([]multicallCall, 0, len(pairs))
+	for _, p := range pairs {
+		data, err := erc20ABI.Pack("allowance", ownerAddr, p.spender)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pack allowance data: %v", err)), nil
+		}
+		calls = append(calls, multicallCall{Target: p.token, AllowFailure: true, CallData: data})
+	}
+
+	approvals := make([]tokenApproval, 0)
+	if len(calls) > 0 {
+		results, err := multicall(ctx, client, calls)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		for i, r := range results {
+			if !r.Success {
+				continue
+			}
+			allowance, ok := decodeUint256(r.ReturnData)
+			if !ok || allowance.Sign() == 0 {
+				continue
+			}
+
+			p := pairs[i]
+			approval := tokenApproval{
+				Token:     p.token.Hex(),
+				Spender:   p.spender.Hex(),
+				Allowance: allowance.String(),
+				Unlimited: allowance.Cmp(unlimitedAllowanceThreshold) >= 0,
+			}
+			if t, ok := known[p.token]; ok {
+				approval.Symbol, approval.Decimals = t.Symbol, t.Decimals
+			} else if metadata, err := s.tokenInfo(ctx, client, chainID, p.token.Hex()); err == nil {
+				approval.Symbol, approval.Decimals = metadata.Symbol, metadata.Decimals
+			}
+			approval.Formatted = formatUnits(allowance, approval.Decimals)
+			if approval.Unlimited {
+				approval.Formatted = "unlimited"
+			}
+			approvals = append(approvals, approval)
+		}
+	}
+
+	unlimited := 0
+	for _, a := range approvals {
+		if a.Unlimited {
+			unlimited++
+		}
+	}
+
+	responseData := map[string]interface{}{
+		"owner":          ownerAddr.Hex(),
+		"chainId":        chainID.String(),
+		"approvals":      approvals,
+		"unlimitedCount": unlimited,
+		"pairsChecked":   len(pairs),
+	}
+	if lookbackBlocks > 0 {
+		responseData["logsScannedFromBlock"] = scannedFrom
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) revokeApprovalHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	owner := getStringArg(request, "owner")
+	token := getStringArg(request, "tokenAddress")
+	spender := getStringArg(request, "spenderAddress")
+
+	if err := ValidateAddress("owner", owner); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddress("tokenAddress", token); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddress("spenderAddress", spender); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	erc20ABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse ERC20 ABI: %v", err)), nil
+	}
+
+	ownerAddr := common.HexToAddress(owner)
+	tokenAddr := common.HexToAddress(token)
+	spenderAddr := common.HexToAddress(spender)
+
+	// Check the current allowance so an already revoked approval doesn't cost gas
+	allowanceData, err := erc20ABI.Pack("allowance", ownerAddr, spenderAddr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pack allowance data: %v", err)), nil
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &tokenAddr, Data: allowanceData}, nil)
+	if err != nil {
+		if lacksContract(ctx, client, tokenAddr) {
+			return mcp.NewToolResultError(noContractError(tokenAddr).Error()), nil
+		}
+		return revertErrorResult("failed to call allowance", err), nil
+	}
+	allowance, ok := decodeUint256(result)
+	if !ok {
+		// An address without code answers every call with empty data
+		if lacksContract(ctx, client, tokenAddr) {
+			return mcp.NewToolResultError(noContractError(tokenAddr).Error()), nil
+		}
+		return mcp.NewToolResultError("failed to unpack allowance: unexpected return data"), nil
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	responseData := map[string]interface{}{
+		"owner":            ownerAddr.Hex(),
+		"tokenAddress":     tokenAddr.Hex(),
+		"spenderAddress":   spenderAddr.Hex(),
+		"currentAllowance": allowance.String(),
+		"chainId":          chainID.String(),
+	}
+
+	if allowance.Sign() == 0 {
+		responseData["alreadyRevoked"] = true
+	} else {
+		data, err := erc20ABI.Pack("approve", spenderAddr, big.NewInt(0))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pack approve data: %v", err)), nil
+		}
+
+		txRequest := map[string]interface{}{
+			"from":    ownerAddr.Hex(),
+			"to":      tokenAddr.Hex(),
+			"data":    hexutil.Encode(data),
+			"value":   "0x0",
+			"chainId": chainID.Int64(),
+		}
+		if gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: ownerAddr, To: &tokenAddr, Data: data}); err == nil {
+			txRequest["gasLimit"] = hexutil.EncodeUint64(gas)
+		}
+
+		responseData["alreadyRevoked"] = false
+		responseData["transactionRequest"] = txRequest
+		responseData["note"] = "Sign and broadcast transactionRequest with the owner's wallet to set the allowance to zero."
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+var (
+	// permitSelectors are EIP-2612 view functions; a token answering both supports permit
+	domainSeparatorSelector = crypto.Keccak256([]byte("DOMAIN_SEPARATOR()"))[:4]
+	noncesSelector          = crypto.Keccak256([]byte("nonces(address)"))[:4]
+
+	// resetFirstTokens must have their allowance set to zero before it can be changed
+	// to another non-zero value (USDT on Ethereum)
+	resetFirstTokens = map[int64]string{1: "0xdAC17F958D2ee523a2206206994597C13D831ec7"}
+)
+
+// approvalQuote is the subset of a get-quote response needed to plan its approval
+type approvalQuote struct {
+	Action struct {
+		FromChainID int64  `json:"fromChainId"`
+		FromAmount  string `json:"fromAmount"`
+		FromAddress string `json:"fromAddress"`
+		FromToken   struct {
+			Address  string `json:"address"`
+			Symbol   string `json:"symbol"`
+			Decimals int    `json:"decimals"`
+		} `json:"fromToken"`
+	} `json:"action"`
+	Estimate struct {
+		ApprovalAddress string `json:"approvalAddress"`
+	} `json:"estimate"`
+}
+
+// supportsPermit reports whether a token exposes the EIP-2612 DOMAIN_SEPARATOR and nonces views
+func supportsPermit(ctx context.Context, client *ethclient.Client, token, owner common.Address) bool {
+	nonceCall := append(append([]byte{}, noncesSelector...), common.LeftPadBytes(owner.Bytes(), 32)...)
+	for _, data := range [][]byte{domainSeparatorSelector, nonceCall} {
+		result, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+		if err != nil || len(result) != 32 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) planApprovalHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	rpcUrl := getStringArg(request, "rpcUrl")
+	quoteObj := getObjectArg(request, "quote")
+	if quoteObj == nil {
+		return mcp.NewToolResultError("quote object is required"), nil
+	}
+	raw, err := json.Marshal(quoteObj)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid quote: %v", err)), nil
+	}
+	var quote approvalQuote
+	if err := json.Unmarshal(raw, &quote); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid quote: %v", err)), nil
+	}
+
+	action := quote.Action
+	if err := ValidateAddress("quote.action.fromAddress", action.FromAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateTokenAddress("quote.action.fromToken.address", action.FromToken.Address); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAmount("quote.action.fromAmount", action.FromAmount); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if action.FromChainID <= 0 {
+		return mcp.NewToolResultError("quote.action.fromChainId is required"), nil
+	}
+
+	ownerAddr := common.HexToAddress(action.FromAddress)
+	tokenAddr := common.HexToAddress(action.FromToken.Address)
+	amount, _ := new(big.Int).SetString(action.FromAmount, 10)
+
+	responseData := map[string]interface{}{
+		"chainId":      action.FromChainID,
+		"owner":        ownerAddr.Hex(),
+		"tokenAddress": tokenAddr.Hex(),
+		"symbol":       action.FromToken.Symbol,
+		"amount":       amount.String(),
+	}
+
+	// Native tokens are sent as value and never need an approval
+	if tokenAddr == (common.Address{}) {
+		responseData["approvalRequired"] = false
+		responseData["reason"] = "native token"
+		return marshalApprovalPlan(responseData)
+	}
+
+	spender := quote.Estimate.ApprovalAddress
+	if spender == "" {
+		spender = LiFiDiamondAddress
+	}
+	if err := ValidateAddress("quote.estimate.approvalAddress", spender); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	spenderAddr := common.HexToAddress(spender)
+	responseData["spender"] = spenderAddr.Hex()
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, fmt.Sprint(action.FromChainID), rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	erc20ABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse ERC20 ABI: %v", err)), nil
+	}
+
+	// Read the current allowance, the balance and the Permit2 allowance
+	readUint := func(method string, args ...interface{}) (*big.Int, error) {
+		data, err := erc20ABI.Pack(method, args...)
+		if err != nil {
+			return nil, err
+		}
+		result, err := client.CallContract(ctx, ethereum.CallMsg{To: &tokenAddr, Data: data}, nil)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := decodeUint256(result)
+		if !ok {
+			return nil, fmt.Errorf("unexpected return data from %s", method)
+		}
+		return value, nil
+	}
+	allowance, err := readUint("allowance", ownerAddr, spenderAddr)
+	if err != nil {
+		// An address without code answers every call with empty data
+		if lacksContract(ctx, client, tokenAddr) {
+			return mcp.NewToolResultError(noContractError(tokenAddr).Error()), nil
+		}
+		return revertErrorResult("failed to call allowance", err), nil
+	}
+	balance, err := readUint("balanceOf", ownerAddr)
+	if err != nil {
+		return revertErrorResult("failed to call balanceOf", err), nil
+	}
+
+	missing := new(big.Int).Sub(amount, allowance)
+	if missing.Sign() < 0 {
+		missing.SetInt64(0)
+	}
+	required := missing.Sign() > 0
+
+	responseData["currentAllowance"] = allowance.String()
+	responseData["missingAllowance"] = missing.String()
+	responseData["approvalRequired"] = required
+	responseData["balance"] = balance.String()
+	responseData["sufficientBalance"] = balance.Cmp(amount) >= 0
+	responseData["supportsPermit"] = supportsPermit(ctx, client, tokenAddr, ownerAddr)
+	if permit2Allowance, err := readUint("allowance", ownerAddr, common.HexToAddress(Permit2Address)); err == nil {
+		responseData["permit2Allowance"] = permit2Allowance.String()
+		responseData["permit2Approved"] = permit2Allowance.Cmp(amount) >= 0
+	}
+	if decimals := action.FromToken.Decimals; decimals > 0 {
+		responseData["missingAllowanceFormatted"] = formatUnits(missing, decimals)
+	}
+
+	if required {
+		// Approve exactly the quoted amount; tokens like USDT need a reset to zero first
+		resetFirst := allowance.Sign() > 0 && strings.EqualFold(resetFirstTokens[action.FromChainID], tokenAddr.Hex())
+		responseData["resetFirst"] = resetFirst
+
+		var txRequests []map[string]interface{}
+		amounts := []*big.Int{amount}
+		if resetFirst {
+			amounts = []*big.Int{big.NewInt(0), amount}
+		}
+		for _, value := range amounts {
+			data, err := erc20ABI.Pack("approve", spenderAddr, value)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to pack approve data: %v", err)), nil
+			}
+			txRequest := map[string]interface{}{
+				"from":    ownerAddr.Hex(),
+				"to":      tokenAddr.Hex(),
+				"data":    hexutil.Encode(data),
+				"value":   "0x0",
+				"chainId": action.FromChainID,
+			}
+			if gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: ownerAddr, To: &tokenAddr, Data: data}); err == nil {
+				txRequest["gasLimit"] = hexutil.EncodeUint64(gas)
+			}
+			txRequests = append(txRequests, txRequest)
+		}
+		responseData["transactionRequests"] = txRequests
+		responseData["note"] = "Sign and broadcast transactionRequests in order with the owner's wallet before executing the quote."
+	}
+
+	return marshalApprovalPlan(responseData)
+}
+
+// marshalApprovalPlan serializes a plan-approval response
+func marshalApprovalPlan(responseData map[string]interface{}) (*mcp.CallToolResult, error) {
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
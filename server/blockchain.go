@@ -3,13 +3,16 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -40,12 +43,12 @@ func (s *Server) getNativeTokenBalanceHandler(ctx context.Context, request mcp.C
 		return mcp.NewToolResultError(fmt.Sprintf("invalid Ethereum address format: %s", address)), nil
 	}
 
-	// Connect to the Ethereum client
-	client, err := ethclient.Dial(rpcUrl)
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to connect to the Ethereum client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-	defer client.Close()
+	defer release()
 
 	// Convert address string to common.Address
 	accountAddress := common.HexToAddress(address)
@@ -70,7 +73,7 @@ func (s *Server) getNativeTokenBalanceHandler(ctx context.Context, request mcp.C
 
 	// Format the result
 	result := map[string]interface{}{
-		"address":     address,
+		"address":     checksumAddress(address),
 		"balance":     balance.String(),
 		"tokenSymbol": symbol,
 		"chainId":     chainID.String(),
@@ -113,12 +116,12 @@ func (s *Server) getTokenBalanceHandler(ctx context.Context, request mcp.CallToo
 		return mcp.NewToolResultError(fmt.Sprintf("invalid wallet address format: %s", walletAddress)), nil
 	}
 
-	// Connect to the Ethereum client
-	client, err := ethclient.Dial(rpcUrl)
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to connect to the Ethereum client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-	defer client.Close()
+	defer release()
 
 	// Parse the ERC20 ABI
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
@@ -130,6 +133,19 @@ func (s *Server) getTokenBalanceHandler(ctx context.Context, request mcp.CallToo
 	tokenAddr := common.HexToAddress(tokenAddress)
 	walletAddr := common.HexToAddress(walletAddress)
 
+	// Get chain ID to include in the response
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	// Get token information first: a cache miss also checks that the token exists on
+	// this chain before it is called
+	token, err := s.tokenInfo(ctx, client, chainID, tokenAddress)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get token info for %s: %v", tokenAddress, err)), nil
+	}
+
 	// Pack the input data for the balanceOf function
 	data, err := parsedABI.Pack("balanceOf", walletAddr)
 	if err != nil {
@@ -155,10 +171,146 @@ func (s *Server) getTokenBalanceHandler(ctx context.Context, request mcp.CallToo
 		return mcp.NewToolResultError(fmt.Sprintf("failed to unpack result: %v", err)), nil
 	}
 
-	// Get token information
-	tokenSymbol, tokenDecimals, err := getTokenInfo(ctx, client, tokenAddress)
+	// Format the result
+	responseData := map[string]interface{}{
+		"walletAddress": checksumAddress(walletAddress),
+		"tokenAddress":  checksumAddress(tokenAddress),
+		"balance":       balance.String(),
+		"tokenSymbol":   token.Symbol,
+		"decimals":      token.Decimals,
+		"chainId":       chainID.String(),
+	}
+	if token.NonStandard {
+		responseData["nonStandard"] = true
+		responseData["decimalsDefaulted"] = token.DecimalsDefaulted
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get token info for %s: %v", tokenAddress, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+const (
+	// maxBatchTokens caps the number of tokens in a single get-token-balances call
+	maxBatchTokens = 100
+)
+
+func (s *Server) getTokenBalancesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	walletAddress := getStringArg(request, "walletAddress")
+	tokenAddresses := getArrayArg(request, "tokenAddresses")
+
+	if err := ValidateAddress("walletAddress", walletAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(tokenAddresses) == 0 {
+		return mcp.NewToolResultError("tokenAddresses array is required and must not be empty"), nil
+	}
+	if len(tokenAddresses) > maxBatchTokens {
+		return mcp.NewToolResultError(fmt.Sprintf("at most %d tokens can be queried per call", maxBatchTokens)), nil
+	}
+
+	tokens := make([]common.Address, len(tokenAddresses))
+	for i, t := range tokenAddresses {
+		tokenAddress, _ := t.(string)
+		if err := ValidateAddress(fmt.Sprintf("tokenAddresses[%d]", i), tokenAddress); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		tokens[i] = common.HexToAddress(tokenAddress)
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	// Parse the ERC20 ABI
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse ERC20 ABI: %v", err)), nil
+	}
+
+	balanceOfData, err := parsedABI.Pack("balanceOf", common.HexToAddress(walletAddress))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pack balanceOf data: %v", err)), nil
+	}
+	symbolData, err := parsedABI.Pack("symbol")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pack symbol data: %v", err)), nil
+	}
+	decimalsData, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pack decimals data: %v", err)), nil
+	}
+
+	// balanceOf, symbol and decimals for every token in one round-trip
+	calls := make([]multicallCall, 0, len(tokens)*3)
+	for _, token := range tokens {
+		calls = append(calls,
+			multicallCall{Target: token, AllowFailure: true, CallData: balanceOfData},
+			multicallCall{Target: token, AllowFailure: true, CallData: symbolData},
+			multicallCall{Target: token, AllowFailure: true, CallData: decimalsData},
+		)
+	}
+
+	results, err := multicall(ctx, client, calls)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	balances := make([]map[string]interface{}, 0, len(tokens))
+	for i, token := range tokens {
+		balanceResult, symbolResult, decimalsResult := results[i*3], results[i*3+1], results[i*3+2]
+
+		entry := map[string]interface{}{
+			"tokenAddress": token.Hex(),
+		}
+
+		balance, ok := decodeUint256(balanceResult.ReturnData)
+		if !balanceResult.Success || !ok {
+			entry["error"] = "balanceOf call failed: the address is not an ERC20 token on this chain (check that it has contract code here)"
+			balances = append(balances, entry)
+			continue
+		}
+		entry["balance"] = balance.String()
+
+		// Non-standard tokens get the same fallbacks as getTokenInfo
+		entry["tokenSymbol"] = unknownTokenSymbol
+		nonStandard := true
+		if symbolResult.Success {
+			if symbol, bytes32, ok := decodeTokenSymbol(parsedABI, symbolResult.ReturnData); ok {
+				entry["tokenSymbol"], nonStandard = symbol, bytes32
+			}
+		}
+		var decimals uint8
+		if decimalsResult.Success && parsedABI.UnpackIntoInterface(&decimals, "decimals", decimalsResult.ReturnData) == nil {
+			entry["decimals"] = int(decimals)
+		} else {
+			entry["decimals"] = 18
+			entry["decimalsDefaulted"] = true
+			nonStandard = true
+		}
+		if nonStandard {
+			entry["nonStandard"] = true
+		}
+
+		balances = append(balances, entry)
 	}
 
 	// Get chain ID to include in the response
@@ -169,12 +321,9 @@ func (s *Server) getTokenBalanceHandler(ctx context.Context, request mcp.CallToo
 
 	// Format the result
 	responseData := map[string]interface{}{
-		"walletAddress": walletAddress,
-		"tokenAddress":  tokenAddress,
-		"balance":       balance.String(),
-		"tokenSymbol":   tokenSymbol,
-		"decimals":      tokenDecimals,
+		"walletAddress": checksumAddress(walletAddress),
 		"chainId":       chainID.String(),
+		"balances":      balances,
 	}
 
 	jsonResponse, err := json.Marshal(responseData)
@@ -225,12 +374,12 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultError(fmt.Sprintf("invalid spender address format: %s", spenderAddress)), nil
 	}
 
-	// Connect to the Ethereum client
-	client, err := ethclient.Dial(rpcUrl)
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to connect to the Ethereum client: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-	defer client.Close()
+	defer release()
 
 	// Parse the ERC20 ABI
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
@@ -243,6 +392,19 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 	ownerAddr := common.HexToAddress(ownerAddress)
 	spenderAddr := common.HexToAddress(spenderAddress)
 
+	// Get chain ID to include in the response
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	// Get token information first: a cache miss also checks that the token exists on
+	// this chain before it is called
+	token, err := s.tokenInfo(ctx, client, chainID, tokenAddress)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get token info for %s: %v", tokenAddress, err)), nil
+	}
+
 	// Pack the allowance function data
 	data, err := parsedABI.Pack("allowance", ownerAddr, spenderAddr)
 	if err != nil {
@@ -255,18 +417,7 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 		Data: data,
 	}, nil) // nil means latest block
 	if err != nil {
-		// Extract detailed revert reason if possible
-		revertReason := "Unknown reason"
-		errorText := err.Error()
-
-		// Try to extract a revert reason from the error message
-		if strings.Contains(errorText, "execution reverted") {
-			if parts := strings.SplitN(errorText, "execution reverted:", 2); len(parts) > 1 {
-				revertReason = strings.TrimSpace(parts[1])
-			}
-		}
-
-		return mcp.NewToolResultError(fmt.Sprintf("failed to call allowance: %v. Revert reason: %s", err, revertReason)), nil
+		return revertErrorResult("failed to call allowance", err), nil
 	}
 
 	// Unpack the allowance
@@ -276,10 +427,84 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultError(fmt.Sprintf("failed to unpack allowance: %v", err)), nil
 	}
 
-	// Get token information for better UX in response
-	tokenSymbol, tokenDecimals, err := getTokenInfo(ctx, client, tokenAddress)
+	// Format the response
+	responseData := map[string]interface{}{
+		"tokenAddress":   checksumAddress(tokenAddress),
+		"tokenSymbol":    token.Symbol,
+		"ownerAddress":   checksumAddress(ownerAddress),
+		"spenderAddress": checksumAddress(spenderAddress),
+		"allowance":      allowance.String(),
+		"decimals":       token.Decimals,
+		"chainId":        chainID.String(),
+	}
+	if token.NonStandard {
+		responseData["nonStandard"] = true
+		responseData["decimalsDefaulted"] = token.DecimalsDefaulted
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get token info for %s: %v", tokenAddress, err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+const (
+	// Receipt polling defaults for wait-for-receipt
+	defaultReceiptTimeout   = 120 * time.Second
+	maxReceiptTimeout       = 600 * time.Second
+	receiptPollInterval     = 2 * time.Second
+	maxReceiptConfirmations = 64
+
+	// receiptSubscriptionPollInterval is the safety-net poll while a newHeads subscription is active
+	receiptSubscriptionPollInterval = 30 * time.Second
+)
+
+func (s *Server) waitForReceiptHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	txHash := getStringArg(request, "txHash")
+	confirmations := mcp.ParseInt(request, "confirmations", 1)
+	timeoutSeconds := mcp.ParseInt(request, "timeoutSeconds", int(defaultReceiptTimeout/time.Second))
+
+	if err := ValidateTxHash("txHash", txHash); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if confirmations < 1 || confirmations > maxReceiptConfirmations {
+		return mcp.NewToolResultError(fmt.Sprintf("confirmations must be between 1 and %d", maxReceiptConfirmations)), nil
+	}
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	if timeout <= 0 || timeout > maxReceiptTimeout {
+		return mcp.NewToolResultError(fmt.Sprintf("timeoutSeconds must be between 1 and %d", int(maxReceiptTimeout/time.Second))), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	receipt, currentBlock, err := waitForReceipt(waitCtx, client, common.HexToHash(txHash), uint64(confirmations))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("timed out after %s waiting for %d confirmation(s) of %s", timeout, confirmations, txHash)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get transaction receipt: %v", err)), nil
 	}
 
 	// Get chain ID to include in the response
@@ -288,16 +513,9 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
 	}
 
-	// Format the response
-	responseData := map[string]interface{}{
-		"tokenAddress":   tokenAddress,
-		"tokenSymbol":    tokenSymbol,
-		"ownerAddress":   ownerAddress,
-		"spenderAddress": spenderAddress,
-		"allowance":      allowance.String(),
-		"decimals":       tokenDecimals,
-		"chainId":        chainID.String(),
-	}
+	responseData := formatReceipt(receipt)
+	responseData["chainId"] = chainID.String()
+	responseData["confirmations"] = currentBlock - receipt.BlockNumber.Uint64() + 1
 
 	jsonResponse, err := json.Marshal(responseData)
 	if err != nil {
@@ -306,3 +524,102 @@ func (s *Server) getAllowanceHandler(ctx context.Context, request mcp.CallToolRe
 
 	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
+
+// waitForReceipt waits until the transaction is mined and has the requested number
+// of confirmations. It returns the receipt and the block number it was last checked at.
+// Clients that support subscriptions (ws:// and wss:// endpoints) re-check on every
+// new head instead of on a fixed interval.
+func waitForReceipt(ctx context.Context, client *ethclient.Client, hash common.Hash, confirmations uint64) (*types.Receipt, uint64, error) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	// A nil channel never fires, so without a subscription only the ticker drives the loop
+	var heads chan *types.Header
+	var subErr <-chan error
+	if client.Client().SupportsSubscriptions() {
+		ch := make(chan *types.Header, 16)
+		if sub, err := client.SubscribeNewHead(ctx, ch); err == nil {
+			defer sub.Unsubscribe()
+			heads, subErr = ch, sub.Err()
+			// Keep a slow poll as a safety net in case heads are missed
+			ticker.Reset(receiptSubscriptionPollInterval)
+		}
+	}
+
+	var receipt *types.Receipt
+	for {
+		if receipt == nil {
+			r, err := client.TransactionReceipt(ctx, hash)
+			if err != nil && !errors.Is(err, ethereum.NotFound) {
+				return nil, 0, err
+			}
+			receipt = r
+		}
+
+		if receipt != nil {
+			head, err := client.BlockNumber(ctx)
+			if err != nil {
+				return nil, 0, err
+			}
+			if head+1 >= receipt.BlockNumber.Uint64()+confirmations {
+				return receipt, head, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-ticker.C:
+		case <-heads:
+		case <-subErr:
+			// The subscription dropped; fall back to polling
+			heads, subErr = nil, nil
+			ticker.Reset(receiptPollInterval)
+		}
+	}
+}
+
+// formatReceipt converts a receipt into a JSON-friendly map with decoded fields
+func formatReceipt(receipt *types.Receipt) map[string]interface{} {
+	status := "success"
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = "reverted"
+	}
+
+	logs := make([]map[string]interface{}, 0, len(receipt.Logs))
+	transfers := make([]tokenTransfer, 0)
+	for _, l := range receipt.Logs {
+		topics := make([]string, len(l.Topics))
+		for i, t := range l.Topics {
+			topics[i] = t.Hex()
+		}
+		logs = append(logs, map[string]interface{}{
+			"address":  l.Address.Hex(),
+			"topics":   topics,
+			"data":     "0x" + common.Bytes2Hex(l.Data),
+			"logIndex": l.Index,
+		})
+		if t, ok := decodeERC20Transfer(l.Address, l.Topics, l.Data); ok {
+			transfers = append(transfers, t)
+		}
+	}
+
+	result := map[string]interface{}{
+		"txHash":            receipt.TxHash.Hex(),
+		"status":            status,
+		"blockNumber":       receipt.BlockNumber.String(),
+		"blockHash":         receipt.BlockHash.Hex(),
+		"gasUsed":           receipt.GasUsed,
+		"cumulativeGasUsed": receipt.CumulativeGasUsed,
+		"logs":              logs,
+		"tokenTransfers":    transfers,
+	}
+	if receipt.EffectiveGasPrice != nil {
+		result["effectiveGasPrice"] = receipt.EffectiveGasPrice.String()
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		result["contractAddress"] = receipt.ContractAddress.Hex()
+	}
+
+	return result
+}
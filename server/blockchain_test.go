@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTokenBalanceChecksCodeOnce(t *testing.T) {
+	var getCodeCalls atomic.Int32
+	rpcURL := newFakeRPC(t, fakeTokenNode(&getCodeCalls))
+	s := newTestServer(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"rpcUrl":        rpcURL,
+		"tokenAddress":  checksummedUSDC,
+		"walletAddress": "0x1111111111111111111111111111111111111111",
+	}
+	for i := 0; i < 2; i++ {
+		result, err := s.getTokenBalanceHandler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("get-token-balance failed: %v %s", err, resultText(result))
+		}
+	}
+	// The first call checks the code along with the metadata; the second is served from cache
+	if n := getCodeCalls.Load(); n != 1 {
+		t.Fatalf("got %d eth_getCode calls, want 1", n)
+	}
+}
+
+func TestRevokeApprovalWithoutContract(t *testing.T) {
+	// An address without code: eth_getCode and every eth_call return empty data
+	rpcURL := newFakeRPC(t, func(method, data string) rpcReply {
+		switch method {
+		case "eth_chainId":
+			return rpcReply{result: "0x1"}
+		case "eth_getCode", "eth_call":
+			return rpcReply{result: "0x"}
+		}
+		return rpcReply{result: nil}
+	})
+	s := newTestServer(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"rpcUrl":         rpcURL,
+		"owner":          "0x1111111111111111111111111111111111111111",
+		"tokenAddress":   checksummedUSDC,
+		"spenderAddress": "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE",
+	}
+	result, err := s.revokeApprovalHandler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(resultText(result), "no contract code") {
+		t.Fatalf("got %q, want a missing contract error", resultText(result))
+	}
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// blockTimeSampleSize is how many blocks back the average block time is measured over
+	blockTimeSampleSize = 10
+
+	// maxBlockTransactions caps the transaction hashes returned by get-block
+	maxBlockTransactions = 500
+)
+
+// blockTags maps the named block tags accepted by get-block to their block numbers
+var blockTags = map[string]rpc.BlockNumber{
+	"latest":    rpc.LatestBlockNumber,
+	"pending":   rpc.PendingBlockNumber,
+	"safe":      rpc.SafeBlockNumber,
+	"finalized": rpc.FinalizedBlockNumber,
+	"earliest":  rpc.EarliestBlockNumber,
+}
+
+// headerSummary describes a block header: its age, gas usage and base fee
+func headerSummary(header *types.Header) map[string]interface{} {
+	summary := map[string]interface{}{
+		"number":     header.Number.String(),
+		"hash":       header.Hash().Hex(),
+		"parentHash": header.ParentHash.Hex(),
+		"timestamp":  header.Time,
+		"ageSeconds": int64(time.Since(time.Unix(int64(header.Time), 0)).Seconds()),
+		"miner":      header.Coinbase.Hex(),
+		"gasUsed":    header.GasUsed,
+		"gasLimit":   header.GasLimit,
+	}
+	if header.GasLimit > 0 {
+		summary["gasUsedPercent"] = float64(header.GasUsed) / float64(header.GasLimit) * 100
+	}
+	if header.BaseFee != nil {
+		summary["baseFeePerGas"] = header.BaseFee.String()
+		summary["baseFeeGwei"] = formatUnits(header.BaseFee, 9)
+	}
+	return summary
+}
+
+// blockTransactionHashes fetches the transaction hashes of a block. The raw RPC call
+// avoids decoding full transactions, which fails on chain-specific transaction types.
+func blockTransactionHashes(ctx context.Context, client *ethclient.Client, hash common.Hash) ([]common.Hash, error) {
+	var block struct {
+		Transactions []common.Hash `json:"transactions"`
+	}
+	if err := client.Client().CallContext(ctx, &block, "eth_getBlockByHash", hash, false); err != nil {
+		return nil, err
+	}
+	return block.Transactions, nil
+}
+
+func (s *Server) getBlockHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	block := strings.ToLower(strings.TrimSpace(getStringArg(request, "block")))
+	includeTransactions := mcp.ParseBoolean(request, "includeTransactions", false)
+	if block == "" {
+		block = "latest"
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	// The block is a tag, a 32-byte hash or a decimal or hex number
+	var header *types.Header
+	switch tag, isTag := blockTags[block]; {
+	case isTag:
+		header, err = client.HeaderByNumber(ctx, big.NewInt(tag.Int64()))
+	case len(block) == 66 && strings.HasPrefix(block, "0x"):
+		if _, err := hexutil.Decode(block); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid block hash: %s", block)), nil
+		}
+		header, err = client.HeaderByHash(ctx, common.HexToHash(block))
+	default:
+		number, parseErr := parseQuantity(block)
+		if parseErr != nil || number.Sign() < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("block must be a number, a block hash or one of latest, pending, safe, finalized, earliest; got '%s'", block)), nil
+		}
+		header, err = client.HeaderByNumber(ctx, number)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get block %s: %v", block, err)), nil
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	responseData := headerSummary(header)
+	responseData["chainId"] = chainID.String()
+
+	if count, err := client.TransactionCount(ctx, header.Hash()); err == nil {
+		responseData["transactionCount"] = count
+	}
+	if includeTransactions {
+		hashes, err := blockTransactionHashes(ctx, client, header.Hash())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get block transactions: %v", err)), nil
+		}
+		if len(hashes) > maxBlockTransactions {
+			hashes = hashes[:maxBlockTransactions]
+			responseData["transactionsTruncated"] = true
+		}
+		responseData["transactions"] = hashes
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) getChainHeadHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get latest block: %v", err)), nil
+	}
+
+	age := time.Since(time.Unix(int64(latest.Time), 0))
+	latestSummary := headerSummary(latest)
+	if count, err := client.TransactionCount(ctx, latest.Hash()); err == nil {
+		latestSummary["transactionCount"] = count
+	}
+
+	responseData := map[string]interface{}{
+		"chainId":     chainID.String(),
+		"blockNumber": latest.Number.Uint64(),
+		"latest":      latestSummary,
+		"stale":       age > rpcMaxBlockAge,
+	}
+
+	// Average block time over the last few blocks
+	if latest.Number.Uint64() > blockTimeSampleSize {
+		past := new(big.Int).Sub(latest.Number, big.NewInt(blockTimeSampleSize))
+		if old, err := client.HeaderByNumber(ctx, past); err == nil && latest.Time >= old.Time {
+			responseData["averageBlockTimeSeconds"] = float64(latest.Time-old.Time) / blockTimeSampleSize
+		}
+	}
+
+	// Finality tags are not supported by every chain or RPC; report what is available
+	for _, name := range []string{"safe", "finalized"} {
+		header, err := client.HeaderByNumber(ctx, big.NewInt(blockTags[name].Int64()))
+		if err != nil || header == nil {
+			continue
+		}
+		responseData[name] = map[string]interface{}{
+			"number":     header.Number.Uint64(),
+			"hash":       header.Hash().Hex(),
+			"lagBlocks":  new(big.Int).Sub(latest.Number, header.Number).Uint64(),
+			"ageSeconds": int64(time.Since(time.Unix(int64(header.Time), 0)).Seconds()),
+		}
+	}
+
+	if progress, err := client.SyncProgress(ctx); err == nil {
+		responseData["syncing"] = progress != nil
+		if progress != nil {
+			responseData["syncHighestBlock"] = strconv.FormatUint(progress.HighestBlock, 10)
+		}
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
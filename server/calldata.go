@@ -0,0 +1,407 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// swapDataComponents is the LibSwap.SwapData struct of the LI.FI Diamond
+const swapDataComponents = `[
+	{"name":"callTo","type":"address"},
+	{"name":"approveTo","type":"address"},
+	{"name":"sendingAssetId","type":"address"},
+	{"name":"receivingAssetId","type":"address"},
+	{"name":"fromAmount","type":"uint256"},
+	{"name":"callData","type":"bytes"},
+	{"name":"requiresDeposit","type":"bool"}
+]`
+
+// LiFiDiamondABI holds the LI.FI Diamond entry points whose full signatures are known
+// here: the GenericSwapFacet functions, plus the ERC20 calls quotes ask for approvals
+var LiFiDiamondABI = `[
+	` + swapFunction("swapTokensGeneric", "tuple[]") + `,
+	` + swapFunction("swapTokensSingleV3ERC20ToERC20", "tuple") + `,
+	` + swapFunction("swapTokensSingleV3ERC20ToNative", "tuple") + `,
+	` + swapFunction("swapTokensSingleV3NativeToERC20", "tuple") + `,
+	` + swapFunction("swapTokensMultipleV3ERC20ToERC20", "tuple[]") + `,
+	` + swapFunction("swapTokensMultipleV3ERC20ToNative", "tuple[]") + `,
+	` + swapFunction("swapTokensMultipleV3NativeToERC20", "tuple[]") + `,
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// swapFunction renders the ABI of a GenericSwapFacet function taking one or many SwapData
+func swapFunction(name, swapDataType string) string {
+	return `{"type":"function","name":"` + name + `","stateMutability":"payable","inputs":[
+		{"name":"_transactionId","type":"bytes32"},
+		{"name":"_integrator","type":"string"},
+		{"name":"_referrer","type":"string"},
+		{"name":"_receiver","type":"address"},
+		{"name":"_minAmountOut","type":"uint256"},
+		{"name":"_swapData","type":"` + swapDataType + `","components":` + swapDataComponents + `}
+	],"outputs":[]}`
+}
+
+// signatureLookupURL is the 4-byte signature database used for unknown selectors
+const signatureLookupURL = "https://www.4byte.directory/api/v1/signatures/?hex_signature="
+
+var (
+	// lifiDiamondABI is the parsed LiFiDiamondABI
+	lifiDiamondABI = func() abi.ABI {
+		parsed, err := abi.JSON(strings.NewReader(LiFiDiamondABI))
+		if err != nil {
+			panic(fmt.Sprintf("invalid LiFiDiamondABI: %v", err))
+		}
+		return parsed
+	}()
+
+	// bridgeDataArgs decodes ILiFi.BridgeData, the first parameter of every bridge facet
+	// function, followed by the SwapData[] that swapAndStartBridgeTokensVia* functions take next
+	bridgeDataArgs = func() abi.Arguments {
+		bridgeData, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+			{Name: "transactionId", Type: "bytes32"},
+			{Name: "bridge", Type: "string"},
+			{Name: "integrator", Type: "string"},
+			{Name: "referrer", Type: "address"},
+			{Name: "sendingAssetId", Type: "address"},
+			{Name: "receiver", Type: "address"},
+			{Name: "minAmount", Type: "uint256"},
+			{Name: "destinationChainId", Type: "uint256"},
+			{Name: "hasSourceSwaps", Type: "bool"},
+			{Name: "hasDestinationCall", Type: "bool"},
+		})
+		if err != nil {
+			panic(fmt.Sprintf("invalid BridgeData type: %v", err))
+		}
+		var components []abi.ArgumentMarshaling
+		if err := json.Unmarshal([]byte(swapDataComponents), &components); err != nil {
+			panic(fmt.Sprintf("invalid SwapData components: %v", err))
+		}
+		swapData, err := abi.NewType("tuple[]", "", components)
+		if err != nil {
+			panic(fmt.Sprintf("invalid SwapData type: %v", err))
+		}
+		return abi.Arguments{{Name: "bridgeData", Type: bridgeData}, {Name: "swapData", Type: swapData}}
+	}()
+)
+
+// abiValueToJSON converts an unpacked ABI value to JSON-friendly data, naming tuple
+// fields after their ABI components
+func abiValueToJSON(t abi.Type, v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch t.T {
+	case abi.TupleTy:
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			if name == "" {
+				name = fmt.Sprintf("field%d", i)
+			}
+			fields[name] = abiValueToJSON(*elem, rv.Field(i).Interface())
+		}
+		return fields
+	case abi.SliceTy, abi.ArrayTy:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = abiValueToJSON(*t.Elem, rv.Index(i).Interface())
+		}
+		return items
+	case abi.FixedBytesTy:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	}
+	return formatABIValue(v)
+}
+
+// decodeArgs unpacks call arguments into a name -> value map
+func decodeArgs(method abi.Method, data []byte) (map[string]interface{}, error) {
+	values, err := method.Inputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	args := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		name := strings.TrimPrefix(method.Inputs[i].Name, "_")
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		args[name] = abiValueToJSON(method.Inputs[i].Type, v)
+	}
+	return args, nil
+}
+
+// decodeBridgeData decodes the BridgeData (and, with source swaps, the SwapData[]) that
+// lead the arguments of LI.FI bridge facet functions. It returns nil if data doesn't
+// look like a bridge call.
+func decodeBridgeData(data []byte) map[string]interface{} {
+	values, err := bridgeDataArgs[:1].Unpack(data)
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+	bridgeData, ok := abiValueToJSON(bridgeDataArgs[0].Type, values[0]).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	// Reject decodes of unrelated calls that happen to parse
+	bridge, _ := bridgeData["bridge"].(string)
+	if bridge == "" || strings.IndexFunc(bridge, func(r rune) bool { return r > unicode.MaxASCII || !unicode.IsPrint(r) }) >= 0 {
+		return nil
+	}
+	destination, ok := new(big.Int).SetString(fmt.Sprint(bridgeData["destinationChainId"]), 10)
+	if !ok || destination.Sign() <= 0 || !destination.IsInt64() {
+		return nil
+	}
+
+	result := map[string]interface{}{"bridgeData": bridgeData}
+	if hasSwaps, _ := bridgeData["hasSourceSwaps"].(bool); hasSwaps {
+		if values, err := bridgeDataArgs.Unpack(data); err == nil && len(values) == 2 {
+			result["swapData"] = abiValueToJSON(bridgeDataArgs[1].Type, values[1])
+		}
+	}
+	return result
+}
+
+// lookupSignatures returns the text signatures registered for a selector in the 4-byte
+// signature database, oldest (most likely canonical) first
+func (s *Server) lookupSignatures(ctx context.Context, selector string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signatureLookupURL+selector, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.externalHTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signature lookup returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Results []struct {
+			ID            int    `json:"id"`
+			TextSignature string `json:"text_signature"`
+		} `json:"results"`
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse signature lookup response: %v", err)
+	}
+
+	signatures := make([]string, 0, len(result.Results))
+	for i := len(result.Results) - 1; i >= 0; i-- {
+		signatures = append(signatures, result.Results[i].TextSignature)
+	}
+	return signatures, nil
+}
+
+// methodFromSignature builds an ABI method from a text signature such as "transfer(address,uint256)"
+func methodFromSignature(signature string) (abi.Method, error) {
+	selector, err := abi.ParseSelector(signature)
+	if err != nil {
+		return abi.Method{}, err
+	}
+	encoded, err := json.Marshal([]abi.SelectorMarshaling{selector})
+	if err != nil {
+		return abi.Method{}, err
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(encoded)))
+	if err != nil {
+		return abi.Method{}, err
+	}
+	return parsed.Methods[selector.Name], nil
+}
+
+func (s *Server) decodeCalldataHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Get parameters
+	dataHex := getStringArg(request, "data")
+	to := getStringArg(request, "to")
+	// Off by default: the lookup sends the selector to a third party
+	lookup := mcp.ParseBoolean(request, "lookupSignature", false)
+	expectedReceiver := getStringArg(request, "expectedReceiver")
+
+	data, err := hexutil.Decode(dataHex)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("data: invalid hex calldata: %v", err)), nil
+	}
+	if len(data) < 4 {
+		return mcp.NewToolResultError("data: calldata must contain at least a 4-byte function selector"), nil
+	}
+	if to != "" {
+		if err := ValidateAddress("to", to); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if expectedReceiver != "" {
+		if err := ValidateAddress("expectedReceiver", expectedReceiver); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	selector := hexutil.Encode(data[:4])
+	responseData := map[string]interface{}{
+		"selector": selector,
+	}
+	if to != "" {
+		responseData["to"] = common.HexToAddress(to).Hex()
+		responseData["toIsLiFiDiamond"] = strings.EqualFold(to, LiFiDiamondAddress)
+	}
+
+	// Known LI.FI and ERC20 functions decode with parameter names
+	method, err := lifiDiamondABI.MethodById(data[:4])
+	if err == nil {
+		args, err := decodeArgs(*method, data[4:])
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("calldata does not match %s: %v", method.Sig, err)), nil
+		}
+		responseData["function"] = method.Name
+		responseData["signature"] = method.Sig
+		responseData["source"] = "lifi-abi"
+		responseData["args"] = args
+	} else {
+		// Bridge facets all take BridgeData first, whatever their facet-specific data
+		if bridge := decodeBridgeData(data[4:]); bridge != nil {
+			for k, v := range bridge {
+				responseData[k] = v
+			}
+		}
+
+		// Fall back to the public signature database for names and the remaining arguments
+		if lookup {
+			signatures, err := s.lookupSignatures(ctx, selector)
+			if err != nil {
+				responseData["signatureLookupError"] = err.Error()
+			}
+			for _, sig := range signatures {
+				m, err := methodFromSignature(sig)
+				if err != nil {
+					continue
+				}
+				args, err := decodeArgs(m, data[4:])
+				if err != nil {
+					continue
+				}
+				responseData["function"] = m.Name
+				responseData["signature"] = m.Sig
+				responseData["source"] = "4byte"
+				responseData["args"] = args
+				break
+			}
+		}
+		if _, ok := responseData["function"]; !ok {
+			if _, ok := responseData["bridgeData"]; !ok {
+				if lookup {
+					responseData["warning"] = "Unknown function: the selector is not in the LI.FI ABI or the signature database. Do not sign calldata you cannot verify."
+				} else {
+					responseData["warning"] = "Unknown function: the selector is not in the LI.FI ABI. Pass lookupSignature=true to look it up on 4byte.directory. Do not sign calldata you cannot verify."
+				}
+			}
+		}
+	}
+
+	// Compare the encoded receiver with the one the caller expects
+	if expectedReceiver != "" {
+		if receiver, ok := calldataReceiver(data); ok {
+			matches := receiver == common.HexToAddress(expectedReceiver)
+			responseData["receiver"] = receiver.Hex()
+			responseData["receiverMatches"] = matches
+			if !matches {
+				responseData["warning"] = fmt.Sprintf("The calldata sends funds to %s, not the expected receiver %s. Do not sign it.", receiver.Hex(), common.HexToAddress(expectedReceiver).Hex())
+			}
+		} else {
+			responseData["receiverNote"] = "No receiver to check: not a LI.FI swap or bridge call, a non-EVM destination, or a destination call whose receiver is the executor contract."
+		}
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// nonEVMReceiver is the placeholder LI.FI encodes as the receiver when funds go to a
+// non-EVM chain; the real recipient is in the facet-specific data
+const nonEVMReceiver = "0x11f111f111f111F111f111f111F111f111f111f1"
+
+// calldataReceiver returns the receiver encoded in LI.FI Diamond calldata: the
+// _receiver of a GenericSwapFacet call or the BridgeData receiver of a bridge call.
+// It reports false when the calldata has no receiver to check, including bridge calls
+// with a destination call, whose receiver is the destination executor contract.
+func calldataReceiver(data []byte) (common.Address, bool) {
+	if len(data) < 4 {
+		return common.Address{}, false
+	}
+	if method, err := lifiDiamondABI.MethodById(data[:4]); err == nil {
+		args, err := decodeArgs(*method, data[4:])
+		if err != nil {
+			return common.Address{}, false
+		}
+		receiver, ok := args["receiver"].(string)
+		return common.HexToAddress(receiver), ok && common.IsHexAddress(receiver)
+	}
+
+	bridge := decodeBridgeData(data[4:])
+	if bridge == nil {
+		return common.Address{}, false
+	}
+	bridgeData := bridge["bridgeData"].(map[string]interface{})
+	if hasCall, _ := bridgeData["hasDestinationCall"].(bool); hasCall {
+		return common.Address{}, false
+	}
+	receiver, _ := bridgeData["receiver"].(string)
+	if !common.IsHexAddress(receiver) || strings.EqualFold(receiver, nonEVMReceiver) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(receiver), true
+}
+
+// checkQuoteReceiver verifies that a quote's transactionRequest pays out to the
+// expected recipient, so a quote whose receiver was swapped (e.g. by a prompt
+// injection) is rejected before anyone signs it. When the calldata has no receiver it
+// can decode, it returns a warning instead, since the quote can't be checked.
+func checkQuoteReceiver(body []byte, expected string) (string, error) {
+	var quote struct {
+		TransactionRequest struct {
+			Data string `json:"data"`
+		} `json:"transactionRequest"`
+	}
+	if err := json.Unmarshal(body, &quote); err != nil {
+		return "", fmt.Errorf("failed to parse quote response: %v", err)
+	}
+	if quote.TransactionRequest.Data == "" {
+		return "", nil
+	}
+
+	var receiver common.Address
+	data, err := hexutil.Decode(quote.TransactionRequest.Data)
+	ok := err == nil
+	if ok {
+		receiver, ok = calldataReceiver(data)
+	}
+	if !ok {
+		return fmt.Sprintf("The receiver in transactionRequest.data could not be verified against the requested recipient %s: it is not a recognised LI.FI swap or bridge call, or it pays out through a destination call. Check the recipient before signing.", common.HexToAddress(expected).Hex()), nil
+	}
+	if receiver == common.HexToAddress(expected) {
+		return "", nil
+	}
+	return "", fmt.Errorf("quote rejected: its transactionRequest sends funds to %s, not the requested recipient %s. Do not sign it", receiver.Hex(), common.HexToAddress(expected).Hex())
+}
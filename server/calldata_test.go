@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	quoteRecipient = "0x1111111111111111111111111111111111111111"
+	otherRecipient = "0x2222222222222222222222222222222222222222"
+)
+
+// testBridgeData mirrors ILiFi.BridgeData for packing
+type testBridgeData struct {
+	TransactionId      [32]byte
+	Bridge             string
+	Integrator         string
+	Referrer           common.Address
+	SendingAssetId     common.Address
+	Receiver           common.Address
+	MinAmount          *big.Int
+	DestinationChainId *big.Int
+	HasSourceSwaps     bool
+	HasDestinationCall bool
+}
+
+// testSwapData mirrors LibSwap.SwapData for packing
+type testSwapData struct {
+	CallTo           common.Address
+	ApproveTo        common.Address
+	SendingAssetId   common.Address
+	ReceivingAssetId common.Address
+	FromAmount       *big.Int
+	CallData         []byte
+	RequiresDeposit  bool
+}
+
+// swapCalldata encodes a GenericSwapFacet call paying out to receiver
+func swapCalldata(t *testing.T, receiver string) string {
+	t.Helper()
+	data, err := lifiDiamondABI.Pack("swapTokensGeneric", [32]byte{}, "lifi-mcp", "", common.HexToAddress(receiver), big.NewInt(1), []testSwapData{})
+	if err != nil {
+		t.Fatalf("failed to pack swap calldata: %v", err)
+	}
+	return hexutil.Encode(data)
+}
+
+// bridgeCalldata encodes a bridge facet call paying out to receiver
+func bridgeCalldata(t *testing.T, receiver string, destinationCall bool) string {
+	t.Helper()
+	args, err := bridgeDataArgs[:1].Pack(testBridgeData{
+		Bridge:             "stargate",
+		Integrator:         "lifi-mcp",
+		Receiver:           common.HexToAddress(receiver),
+		MinAmount:          big.NewInt(1),
+		DestinationChainId: big.NewInt(10),
+		HasDestinationCall: destinationCall,
+	})
+	if err != nil {
+		t.Fatalf("failed to pack bridge calldata: %v", err)
+	}
+	return hexutil.Encode(append([]byte{0xde, 0xad, 0xbe, 0xef}, args...))
+}
+
+func quoteWithCalldata(data string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"transactionRequest": map[string]interface{}{"data": data},
+	})
+	return body
+}
+
+func TestCheckQuoteReceiver(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "swap to recipient", body: quoteWithCalldata(swapCalldata(t, quoteRecipient))},
+		{name: "bridge to recipient", body: quoteWithCalldata(bridgeCalldata(t, quoteRecipient, false))},
+		{name: "swap to someone else", body: quoteWithCalldata(swapCalldata(t, otherRecipient)), wantErr: true},
+		{name: "bridge to someone else", body: quoteWithCalldata(bridgeCalldata(t, otherRecipient, false)), wantErr: true},
+		{name: "destination call", body: quoteWithCalldata(bridgeCalldata(t, otherRecipient, true)), wantWarning: true},
+		{name: "unknown calldata", body: quoteWithCalldata("0xdeadbeef0000"), wantWarning: true},
+		{name: "malformed hex", body: quoteWithCalldata("0xzz"), wantWarning: true},
+		{name: "no transactionRequest", body: []byte(`{"id":"quote"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := checkQuoteReceiver(tt.body, quoteRecipient)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), common.HexToAddress(otherRecipient).Hex()) {
+				t.Errorf("error %q does not name the encoded receiver", err)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Fatalf("warning = %q, want warning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+
+	if _, err := checkQuoteReceiver([]byte("not json"), quoteRecipient); err == nil {
+		t.Error("expected an error for an unparseable quote")
+	}
+}
+
+func TestAnnotateQuoteReceiverWarning(t *testing.T) {
+	body := quoteWithCalldata("0xdeadbeef0000")
+	warning, err := checkQuoteReceiver(body, quoteRecipient)
+	if err != nil {
+		t.Fatalf("checkQuoteReceiver: %v", err)
+	}
+
+	annotated, err := annotateQuote(body, nil, 0, false, warning)
+	if err != nil {
+		t.Fatalf("annotateQuote: %v", err)
+	}
+	var quote map[string]interface{}
+	if err := json.Unmarshal(annotated, &quote); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if quote["receiverWarning"] != warning {
+		t.Errorf("receiverWarning = %v, want %q", quote["receiverWarning"], warning)
+	}
+	if quote["transactionRequest"] == nil {
+		t.Error("annotated quote lost its transactionRequest")
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// cancelDelay is how long a call runs before the test cancels it
+	cancelDelay = 100 * time.Millisecond
+
+	// promptly bounds how long a canceled call may take to return
+	promptly = time.Second
+)
+
+// stallingAPI answers /v1/chains and /v1/tokens and holds every other request until
+// the caller gives up
+type stallingAPI struct{}
+
+func (stallingAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body interface{}
+	switch req.URL.Path {
+	case "/v1/chains":
+		eth := Token{Address: ZeroAddress, Symbol: "ETH", Decimals: 18, Name: "ETH"}
+		body = ChainData{Chains: []Chain{{ID: 1, Key: "eth", Name: "Ethereum", ChainType: "EVM", NativeToken: eth}}}
+	case "/v1/tokens":
+		body = tokenListResponse{Tokens: map[string][]TokenListEntry{
+			"1": {{Address: ZeroAddress, ChainID: 1, Symbol: "ETH", Decimals: 18, Name: "ETH", PriceUSD: "2500"}},
+		}}
+	default:
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	data, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// newStallingRPC starts a JSON-RPC endpoint that never answers before the caller gives up
+func newStallingRPC(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request context only notices the client going away once the body is drained
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func newCancellationServer(t *testing.T, rpcOverrides map[int]string) *Server {
+	t.Helper()
+	s := New(Options{
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		APIHTTPClient:  &http.Client{Transport: stallingAPI{}},
+		ChainsCacheTTL: -1,
+		RPCOverrides:   rpcOverrides,
+	})
+	t.Cleanup(s.Close)
+	return s
+}
+
+// canceledSoon returns a context that is canceled after cancelDelay
+func canceledSoon(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	time.AfterFunc(cancelDelay, cancel)
+	return ctx
+}
+
+func checkPrompt(t *testing.T, start time.Time) {
+	t.Helper()
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Fatalf("returned %s after cancellation, want within %s", elapsed-cancelDelay, promptly)
+	}
+}
+
+func TestHTTPRetryBackoffCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	_, err := newTestHTTPClient().Get(canceledSoon(t), srv.URL, "")
+	checkPrompt(t, start)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestWaitForReceiptCanceled(t *testing.T) {
+	// The node knows the chain but never has the receipt
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": nil})
+	}))
+	defer srv.Close()
+
+	client, err := ethclient.Dial(srv.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	_, _, err = waitForReceipt(canceledSoon(t), client, common.HexToHash("0x01"), 1)
+	checkPrompt(t, start)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestFanOutToolsCanceled(t *testing.T) {
+	const wallet = "0x1111111111111111111111111111111111111111"
+	tests := []struct {
+		name    string
+		handler func(s *Server) mcpserver.ToolHandlerFunc
+		args    map[string]interface{}
+	}{
+		{
+			name:    "compare-quotes",
+			handler: func(s *Server) mcpserver.ToolHandlerFunc { return s.compareQuotesHandler },
+			args: map[string]interface{}{
+				"fromChain": "1", "toChain": "1", "fromToken": ZeroAddress,
+				"toToken":     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+				"fromAddress": wallet, "fromAmount": "1000000000000000000",
+			},
+		},
+		{
+			name:    "get-wallet-portfolio",
+			handler: func(s *Server) mcpserver.ToolHandlerFunc { return s.getWalletPortfolioHandler },
+			args:    map[string]interface{}{"address": wallet, "chains": "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newCancellationServer(t, map[int]string{1: newStallingRPC(t)})
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tt.name
+			request.Params.Arguments = tt.args
+
+			start := time.Now()
+			result, err := tt.handler(s)(canceledSoon(t), request)
+			checkPrompt(t, start)
+			if err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(resultText(result), "request canceled") {
+				t.Fatalf("got %q, want a request canceled error", resultText(result))
+			}
+		})
+	}
+}
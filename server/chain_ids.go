@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// SolanaChainID is LI.FI's numeric ID for Solana mainnet
+	SolanaChainID = 1151111081099710
+
+	// solanaMainnetGenesis is the CAIP-2 reference of Solana mainnet (the truncated genesis hash)
+	solanaMainnetGenesis = "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
+)
+
+// chainArgNames are the tool arguments that hold a single chain identifier
+var chainArgNames = []string{"chain", "chainId", "fromChain", "toChain", "fromChainId", "toChainId"}
+
+// resolveChainID resolves a chain identifier to its numeric chain ID. Accepted forms
+// are a numeric ID ("42161"), a CAIP-2 ID ("eip155:42161"), a LI.FI key ("arb") and a
+// chain name ("Arbitrum One", matched case-insensitively and ignoring spaces).
+func (s *Server) resolveChainID(ctx context.Context, chain, apiKey string) (int, error) {
+	chain = strings.TrimSpace(chain)
+	if id, err := strconv.Atoi(chain); err == nil {
+		return id, nil
+	}
+
+	// CAIP-2: namespace:reference
+	if namespace, reference, ok := strings.Cut(chain, ":"); ok {
+		switch strings.ToLower(namespace) {
+		case "eip155":
+			if id, err := strconv.Atoi(reference); err == nil && id > 0 {
+				return id, nil
+			}
+		case "solana":
+			if reference == solanaMainnetGenesis {
+				return SolanaChainID, nil
+			}
+		}
+		return 0, fmt.Errorf("unsupported CAIP-2 chain ID '%s'", chain)
+	}
+
+	// Load the chains cache if it is missing or expired
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return 0, fmt.Errorf("failed to load chain data: %v", err)
+	}
+
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
+
+	// Keys are matched before names, and LI.FI names before MetaMask names, so one chain's
+	// key never loses to another chain's name. Two chains matching at the same level make
+	// the identifier ambiguous.
+	compact := func(v string) string { return strings.ToLower(strings.ReplaceAll(v, " ", "")) }
+	want := compact(chain)
+	fields := []func(c Chain) string{
+		func(c Chain) string { return c.Key },
+		func(c Chain) string { return c.Name },
+		func(c Chain) string { return c.Metamask.ChainName },
+	}
+	for _, field := range fields {
+		var matches []string
+		id := 0
+		for _, c := range s.chains.data.Chains {
+			if v := field(c); v != "" && compact(v) == want {
+				matches = append(matches, fmt.Sprintf("%s (%d)", c.Name, c.ID))
+				id = c.ID
+			}
+		}
+		switch {
+		case len(matches) == 1:
+			return id, nil
+		case len(matches) > 1:
+			return 0, fmt.Errorf("chain '%s' is ambiguous: it matches %s; use the numeric chain ID", chain, strings.Join(matches, ", "))
+		}
+	}
+	return 0, fmt.Errorf("chain '%s' not found", chain)
+}
+
+// chainType returns the LI.FI chain type (EVM, SVM or UTXO) of a numeric chain ID.
+// Chains that cannot be looked up are treated as EVM, so validation stays as strict as
+// it was before chain types were considered.
+func (s *Server) chainType(ctx context.Context, chain, apiKey string) string {
+	id, err := strconv.Atoi(chain)
+	if err != nil {
+		return ChainTypeEVM
+	}
+	if id == SolanaChainID {
+		return ChainTypeSVM
+	}
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return ChainTypeEVM
+	}
+
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
+	for _, c := range s.chains.data.Chains {
+		if c.ID == id && c.ChainType != "" {
+			return c.ChainType
+		}
+	}
+	return ChainTypeEVM
+}
+
+// normalizeChainArgs is tool handler middleware that rewrites chain identifiers in the
+// well-known chain arguments to numeric IDs, so every tool accepts keys, names and
+// CAIP-2 IDs. Identifiers that cannot be resolved are passed through unchanged for the
+// tool to report.
+func (s *Server) normalizeChainArgs(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return next(ctx, request)
+		}
+
+		apiKey := APIKeyFromContext(ctx)
+		resolve := func(v string) string {
+			if _, err := strconv.Atoi(strings.TrimSpace(v)); err == nil || strings.TrimSpace(v) == "" {
+				return v
+			}
+			id, err := s.resolveChainID(ctx, v, apiKey)
+			if err != nil {
+				return v
+			}
+			return strconv.Itoa(id)
+		}
+
+		var normalized map[string]interface{}
+		set := func(key string, value interface{}) {
+			if normalized == nil {
+				normalized = make(map[string]interface{}, len(args))
+				for k, v := range args {
+					normalized[k] = v
+				}
+			}
+			normalized[key] = value
+		}
+
+		for _, name := range chainArgNames {
+			if v, ok := args[name].(string); ok {
+				if r := resolve(v); r != v {
+					set(name, r)
+				}
+			}
+		}
+
+		// chains is a comma-separated string or an array of identifiers
+		switch v := args["chains"].(type) {
+		case string:
+			parts := strings.Split(v, ",")
+			for i, p := range parts {
+				parts[i] = resolve(strings.TrimSpace(p))
+			}
+			if joined := strings.Join(parts, ","); joined != v {
+				set("chains", joined)
+			}
+		case []interface{}:
+			items := make([]interface{}, len(v))
+			changed := false
+			for i, item := range v {
+				items[i] = item
+				if str, ok := item.(string); ok {
+					if r := resolve(str); r != str {
+						items[i], changed = r, true
+					}
+				}
+			}
+			if changed {
+				set("chains", items)
+			}
+		}
+
+		// get-token-prices takes chain identifiers inside its tokens objects
+		if items, ok := args["tokens"].([]interface{}); ok {
+			rewritten := make([]interface{}, len(items))
+			changed := false
+			for i, item := range items {
+				rewritten[i] = item
+				obj, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if str, ok := obj["chain"].(string); ok {
+					if r := resolve(str); r != str {
+						copied := make(map[string]interface{}, len(obj))
+						for k, v := range obj {
+							copied[k] = v
+						}
+						copied["chain"] = r
+						rewritten[i], changed = copied, true
+					}
+				}
+			}
+			if changed {
+				set("tokens", rewritten)
+			}
+		}
+
+		if normalized != nil {
+			request.Params.Arguments = normalized
+		}
+		return next(ctx, request)
+	}
+}
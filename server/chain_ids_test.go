@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// seedChains fills the chains cache so resolveChainID never calls LI.FI
+func seedChains(s *Server, chains ...Chain) {
+	s.chains.mu.Lock()
+	defer s.chains.mu.Unlock()
+	s.chains.data = ChainData{Chains: chains}
+	s.chains.initialized = true
+}
+
+func newChainTestServer(t *testing.T) *Server {
+	s := newTestServer(t)
+	seedChains(s,
+		Chain{ID: 1, Key: "eth", Name: "Ethereum", Metamask: MetamaskInfo{ChainName: "Ethereum Mainnet"}},
+		Chain{ID: 10, Key: "opt", Name: "Optimism", Metamask: MetamaskInfo{ChainName: "OP Mainnet"}},
+		Chain{ID: 42161, Key: "arb", Name: "Arbitrum", Metamask: MetamaskInfo{ChainName: "Arbitrum One"}},
+		// One chain's key is another's name: the key wins
+		Chain{ID: 100, Key: "gno", Name: "Gnosis"},
+		Chain{ID: 200, Key: "xdai", Name: "GNO"},
+		// Two chains share a name
+		Chain{ID: 300, Key: "sha", Name: "Shared Chain"},
+		Chain{ID: 301, Key: "shb", Name: "Shared Chain"},
+	)
+	return s
+}
+
+func TestResolveChainID(t *testing.T) {
+	s := newChainTestServer(t)
+
+	tests := []struct {
+		chain   string
+		want    int
+		wantErr string
+	}{
+		{chain: "42161", want: 42161},
+		{chain: " 137 ", want: 137},
+		{chain: "eip155:10", want: 10},
+		{chain: "EIP155:1", want: 1},
+		{chain: "solana:" + solanaMainnetGenesis, want: SolanaChainID},
+		{chain: "arb", want: 42161},
+		{chain: "ARB", want: 42161},
+		{chain: "Optimism", want: 10},
+		{chain: "arbitrum one", want: 42161},
+		{chain: "EthereumMainnet", want: 1},
+		{chain: "gno", want: 100},
+		{chain: "sha", want: 300},
+		{chain: "Shared Chain", wantErr: "ambiguous"},
+		{chain: "eip155:abc", wantErr: "unsupported CAIP-2"},
+		{chain: "eip155:0", wantErr: "unsupported CAIP-2"},
+		{chain: "solana:devnet", wantErr: "unsupported CAIP-2"},
+		{chain: "cosmos:cosmoshub-4", wantErr: "unsupported CAIP-2"},
+		{chain: "atlantis", wantErr: "not found"},
+		{chain: "", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		got, err := s.resolveChainID(context.Background(), tt.chain, "")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveChainID(%q) = %d, %v, want error %q", tt.chain, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("resolveChainID(%q) = %d, %v, want %d", tt.chain, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveChainIDAmbiguous(t *testing.T) {
+	s := newChainTestServer(t)
+	_, err := s.resolveChainID(context.Background(), "shared chain", "")
+	if err == nil || !strings.Contains(err.Error(), "(300)") || !strings.Contains(err.Error(), "(301)") {
+		t.Fatalf("error = %v, want both matching chains named", err)
+	}
+}
+
+func TestNormalizeChainArgs(t *testing.T) {
+	s := newChainTestServer(t)
+
+	var got map[string]interface{}
+	handler := s.normalizeChainArgs(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		got = request.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		var request mcp.CallToolRequest
+		request.Params.Arguments = args
+		if _, err := handler(context.Background(), request); err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return got
+	}
+
+	tokens := []interface{}{
+		map[string]interface{}{"chain": "arb", "address": "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"},
+		map[string]interface{}{"chain": "1", "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+		map[string]interface{}{"chain": "atlantis", "address": "0x0000000000000000000000000000000000000000"},
+		"not an object",
+	}
+	args := map[string]interface{}{
+		"fromChain":   "arb",
+		"toChain":     "eip155:10",
+		"chainId":     "137",
+		"chain":       "Shared Chain",
+		"fromChainId": "atlantis",
+		"toChainId":   "",
+		"chains":      "eth, Optimism,56",
+		"tokens":      tokens,
+		"address":     "arb",
+	}
+	want := map[string]interface{}{
+		"fromChain":   "42161",
+		"toChain":     "10",
+		"chainId":     "137",
+		"chain":       "Shared Chain",
+		"fromChainId": "atlantis",
+		"toChainId":   "",
+		"chains":      "1,10,56",
+		"tokens": []interface{}{
+			map[string]interface{}{"chain": "42161", "address": "0xaf88d065e77c8cC2239327C5EDb3A432268e5831"},
+			map[string]interface{}{"chain": "1", "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+			map[string]interface{}{"chain": "atlantis", "address": "0x0000000000000000000000000000000000000000"},
+			"not an object",
+		},
+		"address": "arb",
+	}
+	if normalized := call(args); !reflect.DeepEqual(normalized, want) {
+		t.Errorf("normalized arguments = %v, want %v", normalized, want)
+	}
+
+	// The caller's arguments are copied, not rewritten in place
+	if args["fromChain"] != "arb" || tokens[0].(map[string]interface{})["chain"] != "arb" {
+		t.Errorf("normalizeChainArgs modified the original arguments: %v", args)
+	}
+
+	// chains given as an array
+	normalized := call(map[string]interface{}{"chains": []interface{}{"opt", "1", float64(56)}})
+	if !reflect.DeepEqual(normalized["chains"], []interface{}{"10", "1", float64(56)}) {
+		t.Errorf("chains = %v, want [10 1 56]", normalized["chains"])
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// ChecksumMode controls how EIP-55 checksums of EVM address arguments are enforced
+type ChecksumMode string
+
+const (
+	// ChecksumOff accepts any hex address
+	ChecksumOff ChecksumMode = "off"
+	// ChecksumMixedCase rejects mixed-case addresses whose checksum doesn't match; all
+	// lowercase or uppercase addresses carry no checksum and are accepted
+	ChecksumMixedCase ChecksumMode = "mixed"
+	// ChecksumStrict requires every address with letters to be checksummed
+	ChecksumStrict ChecksumMode = "strict"
+)
+
+// hexAddressPattern matches a whole EVM address argument
+var hexAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// ParseChecksumMode parses a --address-checksum value
+func ParseChecksumMode(mode string) (ChecksumMode, error) {
+	switch m := ChecksumMode(strings.ToLower(mode)); m {
+	case ChecksumOff, ChecksumMixedCase, ChecksumStrict:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid checksum mode %q (use \"off\", \"mixed\" or \"strict\")", mode)
+}
+
+// checksumAddress returns an EVM address in EIP-55 checksummed form. Handlers apply it
+// to the address fields of their results; anything that isn't an EVM address, such as
+// a Solana or Bitcoin address, is returned unchanged.
+func checksumAddress(address string) string {
+	if !hexAddressPattern.MatchString(address) {
+		return address
+	}
+	return common.HexToAddress(address).Hex()
+}
+
+// checkChecksum validates the EIP-55 checksum of an address under the given mode
+func checkChecksum(field, address string, mode ChecksumMode) error {
+	checksummed := common.HexToAddress(address).Hex()
+	if address[2:] == checksummed[2:] {
+		return nil
+	}
+
+	body := address[2:]
+	mixedCase := strings.ToLower(body) != body && strings.ToUpper(body) != body
+	switch {
+	case mixedCase && mode != ChecksumOff:
+		// The correct checksum is deliberately not echoed: it would bless a typo
+		return &ValidationError{Field: field, Message: fmt.Sprintf("address %s has an invalid EIP-55 checksum; check it for typos", address)}
+	case mode == ChecksumStrict:
+		return &ValidationError{Field: field, Message: fmt.Sprintf("address %s is not EIP-55 checksummed", address)}
+	}
+	return nil
+}
+
+// checkArgChecksums walks tool arguments, including nested objects and arrays, and
+// returns the first address that fails the checksum mode
+func checkArgChecksums(field string, value interface{}, mode ChecksumMode) error {
+	switch v := value.(type) {
+	case string:
+		if hexAddressPattern.MatchString(v) {
+			return checkChecksum(field, v, mode)
+		}
+	case map[string]interface{}:
+		// Sorted so the reported field doesn't depend on map iteration order
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := key
+			if field != "" {
+				name = field + "." + key
+			}
+			if err := checkArgChecksums(name, v[key], mode); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := checkArgChecksums(fmt.Sprintf("%s[%d]", field, i), item, mode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checksumAddresses is tool handler middleware that enforces EIP-55 checksums on EVM
+// address arguments. Results are left alone: 20-byte values that aren't addresses
+// (calldata words, topics) and verbatim API payloads must not be rewritten, so handlers
+// checksum their own address fields with checksumAddress.
+func (s *Server) checksumAddresses(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if args, ok := request.Params.Arguments.(map[string]interface{}); ok {
+			if err := checkArgChecksums("", args, s.checksumMode); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	checksummedUSDC = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	lowercaseUSDC   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	// typoUSDC flips the case of one letter of the checksummed address
+	typoUSDC = "0xa0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+)
+
+func TestCheckChecksum(t *testing.T) {
+	tests := []struct {
+		address string
+		mode    ChecksumMode
+		wantErr string
+	}{
+		{checksummedUSDC, ChecksumStrict, ""},
+		{lowercaseUSDC, ChecksumMixedCase, ""},
+		{strings.ToUpper(lowercaseUSDC[2:]), ChecksumMixedCase, ""},
+		{lowercaseUSDC, ChecksumStrict, "not EIP-55 checksummed"},
+		{typoUSDC, ChecksumMixedCase, "invalid EIP-55 checksum"},
+		{typoUSDC, ChecksumStrict, "invalid EIP-55 checksum"},
+		{typoUSDC, ChecksumOff, ""},
+	}
+	for _, tt := range tests {
+		address := tt.address
+		if !strings.HasPrefix(address, "0x") {
+			address = "0x" + address
+		}
+		err := checkChecksum("token", address, tt.mode)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("checkChecksum(%s, %s) = %v, want nil", address, tt.mode, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("checkChecksum(%s, %s) = %v, want %q", address, tt.mode, err, tt.wantErr)
+		}
+	}
+}
+
+func TestChecksumAddressesMiddleware(t *testing.T) {
+	s := &Server{checksumMode: ChecksumMixedCase}
+	// A bytes20 word that happens to look like an address must come back untouched
+	const output = `{"word":"` + lowercaseUSDC + `"}`
+	handler := s.checksumAddresses(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(output), nil
+	})
+
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
+	}
+
+	result := call(map[string]interface{}{"quote": map[string]interface{}{"tokens": []interface{}{typoUSDC}}})
+	if !result.IsError || !strings.Contains(resultText(result), "quote.tokens[0]") {
+		t.Fatalf("got %q, want a checksum error naming quote.tokens[0]", resultText(result))
+	}
+
+	result = call(map[string]interface{}{"token": lowercaseUSDC})
+	if result.IsError || resultText(result) != output {
+		t.Fatalf("got %q, want the result unchanged", resultText(result))
+	}
+}
+
+func TestChecksumAddress(t *testing.T) {
+	tests := map[string]string{
+		lowercaseUSDC: checksummedUSDC,
+		ZeroAddress:   ZeroAddress,
+		// Non-EVM addresses pass through
+		"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM": "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4":   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+	}
+	for in, want := range tests {
+		if got := checksumAddress(in); got != want {
+			t.Errorf("checksumAddress(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestHandlerChecksumsAddressFields(t *testing.T) {
+	rpcURL := newFakeRPC(t, fakeTokenNode(nil))
+	s := newTestServer(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"rpcUrl":         rpcURL,
+		"tokenAddress":   lowercaseUSDC,
+		"ownerAddress":   "0x1111111111111111111111111111111111111111",
+		"spenderAddress": strings.ToLower("0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE"),
+	}
+	result, err := s.getAllowanceHandler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("get-allowance failed: %v %s", err, resultText(result))
+	}
+	text := resultText(result)
+	for _, want := range []string{checksummedUSDC, "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE"} {
+		if !strings.Contains(text, `"`+want+`"`) {
+			t.Errorf("result %s lacks checksummed %s", text, want)
+		}
+	}
+}
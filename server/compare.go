@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxQuoteVariants caps the number of quotes requested by one compare-quotes call
+	maxQuoteVariants = 12
+)
+
+// defaultCompareOrders are the route preferences compared when none are given.
+// RECOMMENDED is the API default and is requested without an order parameter.
+var defaultCompareOrders = []string{"RECOMMENDED", "FASTEST", "CHEAPEST"}
+
+// quoteVariant is one combination of order preference and bridge allow-list
+type quoteVariant struct {
+	Order        string   `json:"order"`
+	AllowBridges []string `json:"allowBridges,omitempty"`
+}
+
+// quoteCost is a fee or gas cost entry of a quote estimate
+type quoteCost struct {
+	AmountUSD string `json:"amountUSD"`
+	Included  *bool  `json:"included,omitempty"`
+}
+
+// quoteSummary is the subset of a /v1/quote response used for comparison
+type quoteSummary struct {
+	ID          string `json:"id"`
+	Tool        string `json:"tool"`
+	ToolDetails struct {
+		Name string `json:"name"`
+	} `json:"toolDetails"`
+	Action struct {
+		FromToken struct {
+			Symbol   string `json:"symbol"`
+			Decimals int    `json:"decimals"`
+		} `json:"fromToken"`
+		ToToken struct {
+			Symbol   string `json:"symbol"`
+			Decimals int    `json:"decimals"`
+		} `json:"toToken"`
+	} `json:"action"`
+	Estimate struct {
+		FromAmount        string      `json:"fromAmount"`
+		ToAmount          string      `json:"toAmount"`
+		ToAmountMin       string      `json:"toAmountMin"`
+		ToAmountUSD       string      `json:"toAmountUSD"`
+		FromAmountUSD     string      `json:"fromAmountUSD"`
+		ExecutionDuration float64     `json:"executionDuration"`
+		FeeCosts          []quoteCost `json:"feeCosts"`
+		GasCosts          []quoteCost `json:"gasCosts"`
+	} `json:"estimate"`
+}
+
+// quoteComparison is one row of the compare-quotes table
+type quoteComparison struct {
+	quoteVariant
+	Tool                     string  `json:"tool,omitempty"`
+	ToAmount                 string  `json:"toAmount,omitempty"`
+	ToAmountFormatted        string  `json:"toAmountFormatted,omitempty"`
+	ToAmountMin              string  `json:"toAmountMin,omitempty"`
+	ToAmountUSD              string  `json:"toAmountUSD,omitempty"`
+	FeeCostsUSD              float64 `json:"feeCostsUSD"`
+	GasCostsUSD              float64 `json:"gasCostsUSD"`
+	ExecutionDurationSeconds float64 `json:"executionDurationSeconds"`
+	Error                    string  `json:"error,omitempty"`
+}
+
+// sumCostsUSD adds up the USD amounts of cost entries, skipping unparseable and non-finite ones
+func sumCostsUSD(costs []quoteCost) float64 {
+	total := 0.0
+	for _, c := range costs {
+		if v, err := strconv.ParseFloat(c.AmountUSD, 64); err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+			total += v
+		}
+	}
+	return total
+}
+
+// parseBridgeSets parses the bridgeSets argument: an array of bridge key arrays
+func parseBridgeSets(items []interface{}) ([][]string, error) {
+	sets := make([][]string, 0, len(items))
+	for i, item := range items {
+		list, ok := item.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("bridgeSets[%d]: expected an array of bridge keys", i)
+		}
+		set := make([]string, 0, len(list))
+		for _, b := range list {
+			set = append(set, fmt.Sprintf("%v", b))
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+func (s *Server) compareQuotesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get all required parameters
+	fromChain := getStringArg(request, "fromChain")
+	toChain := getStringArg(request, "toChain")
+	fromToken := getStringArg(request, "fromToken")
+	toToken := getStringArg(request, "toToken")
+	fromAddress := getStringArg(request, "fromAddress")
+	fromAmount := getStringArg(request, "fromAmount")
+	toAddress := getStringArg(request, "toAddress")
+	slippage := getStringArg(request, "slippage")
+
+	// Validate required parameters
+	if err := ValidateChainID("fromChain", fromChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateChainID("toChain", toChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Addresses are validated for each chain's type (EVM, SVM or UTXO)
+	fromChainType := s.chainType(ctx, fromChain, apiKey)
+	toChainType := s.chainType(ctx, toChain, apiKey)
+	if err := ValidateTokenAddressForChainType("fromToken", fromToken, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateTokenAddressForChainType("toToken", toToken, toChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddressForChainType("fromAddress", fromAddress, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAmount("fromAmount", fromAmount); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if toAddress != "" {
+		if err := ValidateRecipientAddressForChainType("toAddress", toAddress, toChainType); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if err := ValidateSlippage(slippage); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	orders := defaultCompareOrders
+	if ordersArg := getArrayArg(request, "orders"); ordersArg != nil {
+		orders = make([]string, 0, len(ordersArg))
+		for _, o := range ordersArg {
+			order := strings.ToUpper(fmt.Sprintf("%v", o))
+			switch order {
+			case "RECOMMENDED", "FASTEST", "CHEAPEST":
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("orders: unsupported order %q (use RECOMMENDED, FASTEST or CHEAPEST)", order)), nil
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	// No bridge sets means one variant per order with all bridges allowed
+	bridgeSets := [][]string{nil}
+	if setsArg := getArrayArg(request, "bridgeSets"); setsArg != nil {
+		sets, err := parseBridgeSets(setsArg)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		bridgeSets = sets
+	}
+
+	variants := make([]quoteVariant, 0, len(orders)*len(bridgeSets))
+	for _, set := range bridgeSets {
+		for _, order := range orders {
+			variants = append(variants, quoteVariant{Order: order, AllowBridges: set})
+		}
+	}
+	if len(variants) == 0 {
+		return mcp.NewToolResultError("nothing to compare: orders and bridgeSets must not be empty"), nil
+	}
+	if len(variants) > maxQuoteVariants {
+		return mcp.NewToolResultError(fmt.Sprintf("at most %d quotes can be compared per call (orders x bridgeSets = %d)", maxQuoteVariants, len(variants))), nil
+	}
+
+	// Build the shared query parameters
+	base := url.Values{}
+	base.Add("fromChain", fromChain)
+	base.Add("toChain", toChain)
+	base.Add("fromToken", fromToken)
+	base.Add("toToken", toToken)
+	base.Add("fromAddress", fromAddress)
+	base.Add("fromAmount", fromAmount)
+	if toAddress != "" {
+		base.Add("toAddress", toAddress)
+	}
+	if slippage != "" {
+		base.Add("slippage", slippage)
+	}
+
+	results := make([]quoteComparison, len(variants))
+	var wg sync.WaitGroup
+	for i, v := range variants {
+		wg.Add(1)
+		go func(i int, v quoteVariant) {
+			defer wg.Done()
+			results[i] = s.fetchQuoteComparison(ctx, base, v, apiKey)
+		}(i, v)
+	}
+	wg.Wait()
+	// A canceled call would otherwise report every variant as failed
+	if ctx.Err() != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("request canceled: %v", ctx.Err())), nil
+	}
+
+	// Pick the best variant per criterion among the successful quotes
+	best := map[string]int{}
+	var bestOutput *big.Int
+	for i, r := range results {
+		if r.Error != "" {
+			continue
+		}
+		if out, ok := new(big.Int).SetString(r.ToAmount, 10); ok && (bestOutput == nil || out.Cmp(bestOutput) > 0) {
+			bestOutput = out
+			best["highestOutput"] = i
+		}
+		if j, ok := best["fastest"]; !ok || r.ExecutionDurationSeconds < results[j].ExecutionDurationSeconds {
+			best["fastest"] = i
+		}
+		if j, ok := best["lowestCost"]; !ok || r.FeeCostsUSD+r.GasCostsUSD < results[j].FeeCostsUSD+results[j].GasCostsUSD {
+			best["lowestCost"] = i
+		}
+	}
+
+	responseData := map[string]interface{}{
+		"quotes": results,
+		"best":   best,
+		"note":   "best holds indexes into quotes. Call get-quote with the chosen order and allowBridges to get a transactionRequest.",
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// fetchQuoteComparison requests one quote variant and reduces it to a comparison row.
+// Errors are reported in the row so one failing variant doesn't fail the comparison.
+func (s *Server) fetchQuoteComparison(ctx context.Context, base url.Values, v quoteVariant, apiKey string) quoteComparison {
+	result := quoteComparison{quoteVariant: v}
+
+	params := url.Values{}
+	for k, vals := range base {
+		params[k] = vals
+	}
+	if v.Order != "RECOMMENDED" {
+		params.Set("order", v.Order)
+	}
+	if len(v.AllowBridges) > 0 {
+		params.Set("allowBridges", strings.Join(v.AllowBridges, ","))
+	}
+
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/quote?%s", BaseURL, params.Encode()), apiKey)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var quote quoteSummary
+	if err := json.Unmarshal(body, &quote); err != nil {
+		result.Error = fmt.Sprintf("failed to parse quote response: %v", err)
+		return result
+	}
+
+	result.Tool = quote.Tool
+	result.ToAmount = quote.Estimate.ToAmount
+	result.ToAmountMin = quote.Estimate.ToAmountMin
+	result.ToAmountUSD = quote.Estimate.ToAmountUSD
+	result.FeeCostsUSD = sumCostsUSD(quote.Estimate.FeeCosts)
+	result.GasCostsUSD = sumCostsUSD(quote.Estimate.GasCosts)
+	result.ExecutionDurationSeconds = quote.Estimate.ExecutionDuration
+	if amount, ok := new(big.Int).SetString(quote.Estimate.ToAmount, 10); ok {
+		result.ToAmountFormatted = formatUnits(amount, quote.Action.ToToken.Decimals) + " " + quote.Action.ToToken.Symbol
+	}
+	return result
+}
@@ -2,8 +2,8 @@ package server
 
 import (
 	"context"
+	"net"
 	"net/http"
-	"os"
 	"strings"
 )
 
@@ -13,12 +13,18 @@ type contextKey string
 const (
 	// ctxKeyAPIKey is the context key for storing the LI.FI API key
 	ctxKeyAPIKey contextKey = "lifi-api-key"
+
+	// ctxKeyRemoteAddr is the context key for storing the HTTP client's IP address
+	ctxKeyRemoteAddr contextKey = "remote-addr"
 )
 
 // ExtractAPIKeyFromRequest is the HTTPContextFunc for mcp-go's Streamable HTTP server.
 // It extracts the LI.FI API key from the HTTP request headers and stores it in context.
 // Supports both Authorization Bearer token and custom X-LiFi-Api-Key header.
+// The client's IP address is stored too, to key tool budgets of sessionless requests.
 func ExtractAPIKeyFromRequest(ctx context.Context, r *http.Request) context.Context {
+	ctx = withRemoteAddr(ctx, r)
+
 	// Try Authorization header first (Bearer token)
 	auth := r.Header.Get("Authorization")
 	if strings.HasPrefix(auth, "Bearer ") {
@@ -38,14 +44,47 @@ func ExtractAPIKeyFromRequest(ctx context.Context, r *http.Request) context.Cont
 	return ctx
 }
 
-// ExtractAPIKeyFromEnv is the StdioContextFunc for mcp-go's stdio server.
-// It reads the LI.FI API key from the LIFI_API_KEY environment variable and stores it in context.
-func ExtractAPIKeyFromEnv(ctx context.Context) context.Context {
-	apiKey := os.Getenv("LIFI_API_KEY")
-	if apiKey != "" {
-		return context.WithValue(ctx, ctxKeyAPIKey, apiKey)
+// WithDefaultAPIKey returns an HTTPContextFunc that extracts the API key from the request
+// headers like ExtractAPIKeyFromRequest, falling back to defaultKey when the request has none.
+func WithDefaultAPIKey(defaultKey string) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		ctx = ExtractAPIKeyFromRequest(ctx, r)
+		if defaultKey != "" && APIKeyFromContext(ctx) == "" {
+			return context.WithValue(ctx, ctxKeyAPIKey, defaultKey)
+		}
+		return ctx
 	}
-	return ctx
+}
+
+// StaticAPIKey returns a StdioContextFunc that stores the given API key in every request context.
+// An empty key leaves the context unchanged so the default rate limits apply.
+func StaticAPIKey(apiKey string) func(ctx context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		if apiKey != "" {
+			return context.WithValue(ctx, ctxKeyAPIKey, apiKey)
+		}
+		return ctx
+	}
+}
+
+// withRemoteAddr stores the IP address the request came from, without the port, which
+// changes with every connection. Forwarding headers are ignored since clients set them.
+func withRemoteAddr(ctx context.Context, r *http.Request) context.Context {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKeyRemoteAddr, host)
+}
+
+// remoteAddrFromContext returns the HTTP client's IP address, or "" for stdio and
+// in-process calls
+func remoteAddrFromContext(ctx context.Context) string {
+	addr, _ := ctx.Value(ctxKeyRemoteAddr).(string)
+	return addr
 }
 
 // APIKeyFromContext retrieves the LI.FI API key from the request context.
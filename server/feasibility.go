@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxVerifiedBridges caps the per-bridge /v1/connections calls made by check-route-feasibility
+	maxVerifiedBridges = 15
+)
+
+// toolsResponse is the subset of /v1/tools used to find the bridges and exchanges for a chain pair
+type toolsResponse struct {
+	Bridges []struct {
+		Key             string `json:"key"`
+		Name            string `json:"name"`
+		SupportedChains []struct {
+			FromChainID int `json:"fromChainId"`
+			ToChainID   int `json:"toChainId"`
+		} `json:"supportedChains"`
+	} `json:"bridges"`
+	Exchanges []struct {
+		Key             string `json:"key"`
+		Name            string `json:"name"`
+		SupportedChains []int  `json:"supportedChains"`
+	} `json:"exchanges"`
+}
+
+// connectionsResponse is the response shape of /v1/connections
+type connectionsResponse struct {
+	Connections []struct {
+		FromChainID int              `json:"fromChainId"`
+		ToChainID   int              `json:"toChainId"`
+		FromTokens  []TokenListEntry `json:"fromTokens"`
+		ToTokens    []TokenListEntry `json:"toTokens"`
+	} `json:"connections"`
+}
+
+// routeTool is a bridge or exchange that can serve a route
+type routeTool struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// fetchConnections queries /v1/connections for one chain and token pair, optionally
+// restricted to a single bridge
+func (s *Server) fetchConnections(ctx context.Context, fromChain, toChain, fromToken, toToken, bridge, apiKey string) (*connectionsResponse, error) {
+	params := url.Values{}
+	params.Add("fromChain", fromChain)
+	params.Add("toChain", toChain)
+	if fromToken != "" {
+		params.Add("fromToken", fromToken)
+	}
+	if toToken != "" {
+		params.Add("toToken", toToken)
+	}
+	if bridge != "" {
+		encodedBridges, _ := json.Marshal([]string{bridge})
+		params.Add("allowBridges", string(encodedBridges))
+	}
+
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/connections?%s", BaseURL, params.Encode()), apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var connections connectionsResponse
+	if err := json.Unmarshal(body, &connections); err != nil {
+		return nil, fmt.Errorf("failed to parse connections response: %v", err)
+	}
+	return &connections, nil
+}
+
+// supportsPair reports whether a connections response contains a route to toToken,
+// given as an address or symbol (or to any token if toToken is empty)
+func (c *connectionsResponse) supportsPair(toToken string) bool {
+	for _, conn := range c.Connections {
+		if toToken == "" && len(conn.ToTokens) > 0 {
+			return true
+		}
+		for _, t := range conn.ToTokens {
+			if strings.EqualFold(t.Address, toToken) || strings.EqualFold(t.Symbol, toToken) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) checkRouteFeasibilityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	fromChain := getStringArg(request, "fromChain")
+	toChain := getStringArg(request, "toChain")
+	fromToken := getStringArg(request, "fromToken")
+	toToken := getStringArg(request, "toToken")
+	verifyBridges := mcp.ParseBoolean(request, "verifyBridges", false)
+
+	if err := ValidateChainID("fromChain", fromChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateChainID("toChain", toChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	fromChainID, _ := strconv.Atoi(fromChain)
+	toChainID, _ := strconv.Atoi(toChain)
+
+	// Find the bridges (or, within one chain, the exchanges) that cover the chain pair
+	encodedChains, _ := json.Marshal([]int{fromChainID, toChainID})
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/tools?%s", BaseURL, url.Values{"chains": {string(encodedChains)}}.Encode()), apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+	}
+	var tools toolsResponse
+	if err := json.Unmarshal(body, &tools); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse tools response: %v", err)), nil
+	}
+
+	sameChain := fromChainID == toChainID
+	candidates := make([]routeTool, 0)
+	if sameChain {
+		for _, e := range tools.Exchanges {
+			for _, id := range e.SupportedChains {
+				if id == fromChainID {
+					candidates = append(candidates, routeTool{Key: e.Key, Name: e.Name})
+					break
+				}
+			}
+		}
+	} else {
+		for _, b := range tools.Bridges {
+			for _, pair := range b.SupportedChains {
+				if pair.FromChainID == fromChainID && pair.ToChainID == toChainID {
+					candidates = append(candidates, routeTool{Key: b.Key, Name: b.Name})
+					break
+				}
+			}
+		}
+	}
+
+	// Check the token pair across all tools
+	connections, err := s.fetchConnections(ctx, fromChain, toChain, fromToken, toToken, "", apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+	}
+	feasible := connections.supportsPair(toToken)
+
+	responseData := map[string]interface{}{
+		"feasible":  feasible,
+		"fromChain": fromChain,
+		"toChain":   toChain,
+		"sameChain": sameChain,
+		"amountLimits": "LI.FI does not publish per-bridge minimum or maximum amounts; " +
+			"get-quote reports an error naming the limit when an amount is out of range.",
+	}
+	if fromToken != "" {
+		responseData["fromToken"] = fromToken
+	}
+	if toToken != "" {
+		responseData["toToken"] = toToken
+	}
+	if sameChain {
+		responseData["exchanges"] = candidates
+	} else {
+		responseData["bridges"] = candidates
+	}
+
+	if !feasible {
+		switch {
+		case len(candidates) == 0 && !sameChain:
+			responseData["reason"] = "no bridge connects these chains"
+		case fromToken != "" || toToken != "":
+			responseData["reason"] = "the chains are connected, but not for this token pair"
+		default:
+			responseData["reason"] = "LI.FI reports no connections for this chain pair"
+		}
+	}
+
+	// Optionally confirm which candidate bridges carry this exact token pair
+	if verifyBridges && feasible && !sameChain && (fromToken != "" || toToken != "") {
+		checked := candidates
+		if len(checked) > maxVerifiedBridges {
+			checked = checked[:maxVerifiedBridges]
+			responseData["verifiedBridgesTruncated"] = true
+		}
+
+		supported := make([]bool, len(checked))
+		var wg sync.WaitGroup
+		for i, b := range checked {
+			wg.Add(1)
+			go func(i int, key string) {
+				defer wg.Done()
+				c, err := s.fetchConnections(ctx, fromChain, toChain, fromToken, toToken, key, apiKey)
+				supported[i] = err == nil && c.supportsPair(toToken)
+			}(i, b.Key)
+		}
+		wg.Wait()
+		// A canceled check must not be reported as "no bridge supports the pair"
+		if ctx.Err() != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("request canceled: %v", ctx.Err())), nil
+		}
+
+		verified := make([]routeTool, 0)
+		for i, b := range checked {
+			if supported[i] {
+				verified = append(verified, b)
+			}
+		}
+		responseData["verifiedBridges"] = verified
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
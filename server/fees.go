@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// DefaultMaxFeePercent is the share of a transfer's USD value that fees and gas may
+// take before a quote is flagged with a feeWarning
+const DefaultMaxFeePercent = 10.0
+
+// feeWarning checks the fee and gas costs of a quote against its USD value. It returns
+// nil when the costs are within maxPercent, maxPercent is zero, or the quote has no
+// USD prices to compare.
+func feeWarning(summary *quoteSummary, maxPercent float64) map[string]interface{} {
+	if maxPercent <= 0 {
+		return nil
+	}
+	fromUSD, err := strconv.ParseFloat(summary.Estimate.FromAmountUSD, 64)
+	if err != nil || fromUSD <= 0 || math.IsNaN(fromUSD) || math.IsInf(fromUSD, 0) {
+		return nil
+	}
+
+	// Included fees are taken out of the amount and the rest is paid on top, but the
+	// sender loses both
+	feesUSD := sumCostsUSD(summary.Estimate.FeeCosts)
+	gasUSD := sumCostsUSD(summary.Estimate.GasCosts)
+	costPercent := (feesUSD + gasUSD) / fromUSD * 100
+	if costPercent <= maxPercent {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"fromAmountUSD":     fromUSD,
+		"feeCostsUSD":       feesUSD,
+		"gasCostsUSD":       gasUSD,
+		"costPercent":       costPercent,
+		"maxFeePercent":     maxPercent,
+		"costsExceedAmount": feesUSD+gasUSD >= fromUSD,
+		"message": fmt.Sprintf("fees and gas of $%.2f are %.1f%% of the $%.2f being transferred (limit %.1f%%); consider a larger amount or a cheaper route before executing",
+			feesUSD+gasUSD, costPercent, fromUSD, maxPercent),
+	}
+}
+
+// compactQuote normalizes a quote into the figures needed to judge it: the tool, the
+// amounts with their formatted values, the fee and gas totals in USD and the estimated
+// duration. It leaves out the route steps and the transactionRequest.
+func compactQuote(summary *quoteSummary) map[string]interface{} {
+	feesUSD := sumCostsUSD(summary.Estimate.FeeCosts)
+	gasUSD := sumCostsUSD(summary.Estimate.GasCosts)
+	compact := map[string]interface{}{
+		"id":                       summary.ID,
+		"tool":                     summary.Tool,
+		"fromToken":                summary.Action.FromToken.Symbol,
+		"toToken":                  summary.Action.ToToken.Symbol,
+		"fromAmount":               summary.Estimate.FromAmount,
+		"fromAmountUSD":            summary.Estimate.FromAmountUSD,
+		"toAmount":                 summary.Estimate.ToAmount,
+		"toAmountMin":              summary.Estimate.ToAmountMin,
+		"toAmountUSD":              summary.Estimate.ToAmountUSD,
+		"feeCostsUSD":              feesUSD,
+		"gasCostsUSD":              gasUSD,
+		"totalCostUSD":             feesUSD + gasUSD,
+		"executionDurationSeconds": summary.Estimate.ExecutionDuration,
+	}
+	if summary.ToolDetails.Name != "" {
+		compact["toolName"] = summary.ToolDetails.Name
+	}
+	formatted := map[string]struct {
+		amount   string
+		decimals int
+		symbol   string
+	}{
+		"fromAmountFormatted":  {summary.Estimate.FromAmount, summary.Action.FromToken.Decimals, summary.Action.FromToken.Symbol},
+		"toAmountFormatted":    {summary.Estimate.ToAmount, summary.Action.ToToken.Decimals, summary.Action.ToToken.Symbol},
+		"toAmountMinFormatted": {summary.Estimate.ToAmountMin, summary.Action.ToToken.Decimals, summary.Action.ToToken.Symbol},
+	}
+	for key, f := range formatted {
+		if amount, ok := new(big.Int).SetString(f.amount, 10); ok {
+			compact[key] = formatUnits(amount, f.decimals) + " " + f.symbol
+		}
+	}
+	return compact
+}
+
+// annotateQuote adds the amount conversion, when one was made, a fee warning, when the
+// quote's costs are out of proportion, and a receiver warning, when one is given, to a
+// quote response. With summaryOnly the quote itself is replaced by its compactQuote.
+func annotateQuote(body []byte, amountConversion map[string]interface{}, maxFeePercent float64, summaryOnly bool, receiverWarning string) ([]byte, error) {
+	var summary quoteSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse quote response: %v", err)
+	}
+	warning := feeWarning(&summary, maxFeePercent)
+	if amountConversion == nil && warning == nil && receiverWarning == "" && !summaryOnly {
+		return body, nil
+	}
+
+	var quote map[string]interface{}
+	if summaryOnly {
+		quote = compactQuote(&summary)
+	} else if err := json.Unmarshal(body, &quote); err != nil {
+		return nil, fmt.Errorf("failed to parse quote response: %v", err)
+	}
+	if amountConversion != nil {
+		quote["amountConversion"] = amountConversion
+	}
+	if warning != nil {
+		quote["feeWarning"] = warning
+	}
+	if receiverWarning != "" {
+		quote["receiverWarning"] = receiverWarning
+	}
+
+	jsonResponse, err := json.Marshal(quote)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing result: %v", err)
+	}
+	return jsonResponse, nil
+}
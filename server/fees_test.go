@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// summaryWithCosts builds a quote summary from USD figures; nil cost lists are left out
+func summaryWithCosts(t *testing.T, fromAmountUSD string, feeCosts, gasCosts []string) *quoteSummary {
+	t.Helper()
+	estimate := map[string]interface{}{}
+	if fromAmountUSD != "" {
+		estimate["fromAmountUSD"] = fromAmountUSD
+	}
+	costs := func(amounts []string) []map[string]string {
+		list := make([]map[string]string, len(amounts))
+		for i, a := range amounts {
+			list[i] = map[string]string{"amountUSD": a}
+		}
+		return list
+	}
+	if feeCosts != nil {
+		estimate["feeCosts"] = costs(feeCosts)
+	}
+	if gasCosts != nil {
+		estimate["gasCosts"] = costs(gasCosts)
+	}
+	body, _ := json.Marshal(map[string]interface{}{"estimate": estimate})
+
+	var summary quoteSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		t.Fatalf("failed to build quote summary: %v", err)
+	}
+	return &summary
+}
+
+func TestFeeWarning(t *testing.T) {
+	tests := []struct {
+		name          string
+		fromAmountUSD string
+		feeCosts      []string
+		gasCosts      []string
+		maxPercent    float64
+		wantWarning   bool
+		wantPercent   float64
+	}{
+		{name: "within limit", fromAmountUSD: "100", feeCosts: []string{"1"}, gasCosts: []string{"2"}, maxPercent: 10},
+		{name: "over limit", fromAmountUSD: "100", feeCosts: []string{"8"}, gasCosts: []string{"4"}, maxPercent: 10, wantWarning: true, wantPercent: 12},
+		{name: "exactly at limit", fromAmountUSD: "100", feeCosts: []string{"7"}, gasCosts: []string{"3"}, maxPercent: 10},
+		{name: "exactly at fractional limit", fromAmountUSD: "300", feeCosts: []string{"0.6", "0.3"}, gasCosts: []string{"0.3"}, maxPercent: 0.4},
+		{name: "just over limit", fromAmountUSD: "100", feeCosts: []string{"10.01"}, maxPercent: 10, wantWarning: true, wantPercent: 10.01},
+		{name: "fees only", fromAmountUSD: "50", feeCosts: []string{"10"}, maxPercent: 10, wantWarning: true, wantPercent: 20},
+		{name: "missing gasCosts", fromAmountUSD: "100", feeCosts: []string{"3"}, maxPercent: 10},
+		{name: "missing gasCosts over limit", fromAmountUSD: "100", feeCosts: []string{"30"}, maxPercent: 10, wantWarning: true, wantPercent: 30},
+		{name: "gas only", fromAmountUSD: "10", gasCosts: []string{"5"}, maxPercent: 10, wantWarning: true, wantPercent: 50},
+		{name: "unparseable cost ignored", fromAmountUSD: "100", feeCosts: []string{"n/a", "1"}, gasCosts: []string{""}, maxPercent: 10},
+		{name: "NaN cost ignored", fromAmountUSD: "100", feeCosts: []string{"NaN"}, gasCosts: []string{"1"}, maxPercent: 10},
+		{name: "missing fromAmountUSD", feeCosts: []string{"50"}, gasCosts: []string{"50"}, maxPercent: 10},
+		{name: "zero fromAmountUSD", fromAmountUSD: "0", feeCosts: []string{"5"}, gasCosts: []string{"5"}, maxPercent: 10},
+		{name: "unparseable fromAmountUSD", fromAmountUSD: "n/a", feeCosts: []string{"5"}, maxPercent: 10},
+		{name: "NaN fromAmountUSD", fromAmountUSD: "NaN", feeCosts: []string{"5"}, maxPercent: 10},
+		{name: "infinite fromAmountUSD", fromAmountUSD: "Inf", feeCosts: []string{"5"}, maxPercent: 10},
+		{name: "disabled", fromAmountUSD: "100", feeCosts: []string{"90"}, maxPercent: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := feeWarning(summaryWithCosts(t, tt.fromAmountUSD, tt.feeCosts, tt.gasCosts), tt.maxPercent)
+			if (warning != nil) != tt.wantWarning {
+				t.Fatalf("feeWarning = %v, want warning %v", warning, tt.wantWarning)
+			}
+			if warning == nil {
+				return
+			}
+			if got := warning["costPercent"].(float64); got < tt.wantPercent-1e-9 || got > tt.wantPercent+1e-9 {
+				t.Errorf("costPercent = %v, want %v", got, tt.wantPercent)
+			}
+			if _, err := json.Marshal(warning); err != nil {
+				t.Errorf("warning does not serialize: %v", err)
+			}
+		})
+	}
+}
+
+func TestFeeWarningCostsExceedAmount(t *testing.T) {
+	warning := feeWarning(summaryWithCosts(t, "10", []string{"8"}, []string{"4"}), DefaultMaxFeePercent)
+	if warning == nil {
+		t.Fatal("expected a fee warning")
+	}
+	if warning["costsExceedAmount"] != true {
+		t.Errorf("costsExceedAmount = %v, want true", warning["costsExceedAmount"])
+	}
+}
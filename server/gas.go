@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// feeHistoryBlocks is how many recent blocks priority fees are sampled from
+	feeHistoryBlocks = 20
+
+	// defaultGasLimit is the gas used by a plain native transfer
+	defaultGasLimit = 21000
+)
+
+// gasTier is a fee suggestion sampled at one reward percentile of recent blocks
+type gasTier struct {
+	name       string
+	percentile float64
+}
+
+var gasTiers = []gasTier{
+	{name: "slow", percentile: 10},
+	{name: "standard", percentile: 50},
+	{name: "fast", percentile: 90},
+}
+
+// medianBig returns the median of values, or zero if there are none
+func medianBig(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return new(big.Int).Set(sorted[len(sorted)/2])
+}
+
+func (s *Server) getGasPriceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	gasLimit := int64(mcp.ParseInt(request, "gasLimit", defaultGasLimit))
+	if gasLimit <= 0 {
+		return mcp.NewToolResultError("gasLimit must be a positive integer"), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	symbol, decimals, err := s.getNativeTokenInfo(ctx, chainID, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get native token info: %v", err)), nil
+	}
+
+	// The native token price is best effort; costs are still returned in the native token without it
+	var priceUSD string
+	if native, err := s.fetchToken(ctx, chainID.String(), common.Address{}.Hex(), apiKey); err == nil {
+		priceUSD = native.PriceUSD
+	}
+
+	percentiles := make([]float64, len(gasTiers))
+	for i, tier := range gasTiers {
+		percentiles[i] = tier.percentile
+	}
+
+	// Sample base fee and priority fees via eth_feeHistory; chains without EIP-1559
+	// fall back to eth_gasPrice for every tier
+	var baseFee *big.Int
+	priorityFees := make([]*big.Int, len(gasTiers))
+	pricing := "eip1559"
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, percentiles)
+	if err == nil && len(history.BaseFee) > 0 && history.BaseFee[len(history.BaseFee)-1] != nil && history.BaseFee[len(history.BaseFee)-1].Sign() > 0 {
+		// The last entry is the base fee of the next (pending) block
+		baseFee = history.BaseFee[len(history.BaseFee)-1]
+		for i := range gasTiers {
+			samples := make([]*big.Int, 0, len(history.Reward))
+			for _, rewards := range history.Reward {
+				if i < len(rewards) && rewards[i] != nil {
+					samples = append(samples, rewards[i])
+				}
+			}
+			priorityFees[i] = medianBig(samples)
+		}
+	} else {
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get gas price: %v", err)), nil
+		}
+		pricing = "legacy"
+		baseFee = gasPrice
+		for i := range gasTiers {
+			priorityFees[i] = new(big.Int)
+		}
+	}
+
+	gasLimitBig := big.NewInt(gasLimit)
+	tiers := make(map[string]interface{}, len(gasTiers))
+	for i, tier := range gasTiers {
+		priorityFee := priorityFees[i]
+		effectiveFee := new(big.Int).Add(baseFee, priorityFee)
+		// Leave headroom for the base fee to double before the transaction is included
+		maxFee := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), priorityFee)
+		if pricing == "legacy" {
+			maxFee = effectiveFee
+		}
+
+		cost := new(big.Int).Mul(effectiveFee, gasLimitBig)
+		maxCost := new(big.Int).Mul(maxFee, gasLimitBig)
+
+		entry := map[string]interface{}{
+			"maxPriorityFeePerGas":     priorityFee.String(),
+			"maxPriorityFeePerGasGwei": formatUnits(priorityFee, 9),
+			"maxFeePerGas":             maxFee.String(),
+			"maxFeePerGasGwei":         formatUnits(maxFee, 9),
+			"estimatedCost":            cost.String(),
+			"estimatedCostFormatted":   formatUnits(cost, decimals),
+			"maxCost":                  maxCost.String(),
+			"maxCostFormatted":         formatUnits(maxCost, decimals),
+		}
+		if priceUSD != "" {
+			entry["estimatedCostUSD"] = fmt.Sprintf("%.4f", usdValue(cost, decimals, priceUSD))
+			entry["maxCostUSD"] = fmt.Sprintf("%.4f", usdValue(maxCost, decimals, priceUSD))
+		}
+		tiers[tier.name] = entry
+	}
+
+	responseData := map[string]interface{}{
+		"chainId":     chainID.String(),
+		"pricing":     pricing,
+		"baseFee":     baseFee.String(),
+		"baseFeeGwei": formatUnits(baseFee, 9),
+		"gasLimit":    gasLimit,
+		"nativeToken": map[string]interface{}{
+			"symbol":   symbol,
+			"decimals": decimals,
+			"priceUSD": priceUSD,
+		},
+		"tiers": tiers,
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -66,6 +69,11 @@ func (s *Server) getTokensHandler(ctx context.Context, request mcp.CallToolReque
 	chainTypes := getStringArg(request, "chainTypes")
 	minPriceUSD := getStringArg(request, "minPriceUSD")
 
+	opts, err := parsePageOptions(request, defaultTokenFields)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Build the query parameters
 	params := url.Values{}
 	if chains != "" {
@@ -90,7 +98,27 @@ func (s *Server) getTokensHandler(ctx context.Context, request mcp.CallToolReque
 		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(body)), nil
+	if opts.full {
+		return mcp.NewToolResultText(string(body)), nil
+	}
+
+	var chainOrder []string
+	if chains != "" {
+		for _, c := range strings.Split(chains, ",") {
+			chainOrder = append(chainOrder, strings.TrimSpace(c))
+		}
+	}
+	page, err := paginateTokens(body, chainOrder, opts)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonResponse, err := json.Marshal(page)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
 
 func (s *Server) getTokenHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -121,6 +149,34 @@ func (s *Server) getTokenHandler(ctx context.Context, request mcp.CallToolReques
 }
 
 func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.quote(ctx, request, fromAmountQuote)
+}
+
+// getQuoteToAmountHandler quotes an exact output: LI.FI works out the fromAmount
+// needed to deliver toAmount of toToken
+func (s *Server) getQuoteToAmountHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.quote(ctx, request, toAmountQuote)
+}
+
+// quoteKind selects which side of a quote the caller fixes the amount of
+type quoteKind struct {
+	// amountParam is the fixed amount's parameter, "fromAmount" or "toAmount"
+	amountParam string
+	// chainParam and tokenParam name the token amountHuman is denominated in
+	chainParam string
+	tokenParam string
+	// path is the LI.FI endpoint serving this kind of quote
+	path string
+}
+
+var (
+	fromAmountQuote = quoteKind{amountParam: "fromAmount", chainParam: "fromChain", tokenParam: "fromToken", path: "/v1/quote"}
+	toAmountQuote   = quoteKind{amountParam: "toAmount", chainParam: "toChain", tokenParam: "toToken", path: "/v1/quote/toAmount"}
+)
+
+// quote validates the arguments shared by get-quote and get-quote-to-amount, requests
+// the quote and checks and annotates the response
+func (s *Server) quote(ctx context.Context, request mcp.CallToolRequest, kind quoteKind) (*mcp.CallToolResult, error) {
 	apiKey := APIKeyFromContext(ctx)
 
 	// Get all required parameters
@@ -129,7 +185,8 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	fromToken := getStringArg(request, "fromToken")
 	toToken := getStringArg(request, "toToken")
 	fromAddress := getStringArg(request, "fromAddress")
-	fromAmount := getStringArg(request, "fromAmount")
+	amount := getStringArg(request, kind.amountParam)
+	amountHuman := getStringArg(request, "amountHuman")
 
 	// Validate required parameters
 	if err := ValidateChainID("fromChain", fromChain); err != nil {
@@ -138,16 +195,43 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	if err := ValidateChainID("toChain", toChain); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateTokenAddress("fromToken", fromToken); err != nil {
+
+	// Addresses are validated for each chain's type (EVM, SVM or UTXO)
+	fromChainType := s.chainType(ctx, fromChain, apiKey)
+	toChainType := s.chainType(ctx, toChain, apiKey)
+	if err := ValidateTokenAddressForChainType("fromToken", fromToken, fromChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateTokenAddress("toToken", toToken); err != nil {
+	if err := ValidateTokenAddressForChainType("toToken", toToken, toChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateAddress("fromAddress", fromAddress); err != nil {
+	if err := ValidateAddressForChainType("fromAddress", fromAddress, fromChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateAmount("fromAmount", fromAmount); err != nil {
+
+	// Convert a human-readable amount to base units using the token's decimals
+	var amountConversion map[string]interface{}
+	if amountHuman != "" {
+		if amount != "" {
+			return mcp.NewToolResultError(fmt.Sprintf("provide either %s or amountHuman, not both", kind.amountParam)), nil
+		}
+		token, err := s.lookupTokenDecimals(ctx, getStringArg(request, kind.chainParam), getStringArg(request, kind.tokenParam), "", apiKey)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to resolve decimals for %s: %v", kind.tokenParam, err)), nil
+		}
+		units, err := parseUnits(amountHuman, token.Decimals)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("amountHuman: %v", err)), nil
+		}
+		amount = units.String()
+		amountConversion = map[string]interface{}{
+			"amountHuman":    amountHuman,
+			kind.amountParam: amount,
+			"decimals":       token.Decimals,
+			"symbol":         token.Symbol,
+		}
+	}
+	if err := ValidateAmount(kind.amountParam, amount); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
@@ -156,16 +240,35 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	slippage := getStringArg(request, "slippage")
 	integrator := getStringArg(request, "integrator")
 	order := getStringArg(request, "order")
+	maxFeePercent := mcp.ParseFloat64(request, "maxFeePercent", s.maxFeePercent)
+	summaryOnly := mcp.ParseBoolean(request, "summaryOnly", false)
+
+	// Only a fixed fromAmount can have a gas portion taken out of it
+	var fromAmountForGas string
+	if kind == fromAmountQuote {
+		fromAmountForGas = getStringArg(request, "fromAmountForGas")
+	}
 
 	// Validate optional parameters
 	if toAddress != "" {
-		if err := ValidateRecipientAddress("toAddress", toAddress); err != nil {
+		if err := ValidateRecipientAddressForChainType("toAddress", toAddress, toChainType); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 	}
 	if err := ValidateSlippage(slippage); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
+	if fromAmountForGas != "" {
+		if err := ValidateAmount("fromAmountForGas", fromAmountForGas); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		// The gas portion is taken out of fromAmount, so it must leave something to bridge
+		gasAmount, _ := new(big.Int).SetString(fromAmountForGas, 10)
+		total, _ := new(big.Int).SetString(amount, 10)
+		if gasAmount.Cmp(total) >= 0 {
+			return mcp.NewToolResultError("fromAmountForGas must be less than fromAmount"), nil
+		}
+	}
 
 	// Build the query parameters
 	params := url.Values{}
@@ -174,7 +277,7 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	params.Add("fromToken", fromToken)
 	params.Add("toToken", toToken)
 	params.Add("fromAddress", fromAddress)
-	params.Add("fromAmount", fromAmount)
+	params.Add(kind.amountParam, amount)
 
 	if toAddress != "" {
 		params.Add("toAddress", toAddress)
@@ -188,6 +291,9 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	if order != "" {
 		params.Add("order", order)
 	}
+	if fromAmountForGas != "" {
+		params.Add("fromAmountForGas", fromAmountForGas)
+	}
 
 	// Add any array parameters
 	if allowBridges := getArrayArg(request, "allowBridges"); allowBridges != nil {
@@ -207,7 +313,7 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 	}
 
 	// Build the request URL
-	requestURL := fmt.Sprintf("%s/v1/quote?%s", BaseURL, params.Encode())
+	requestURL := fmt.Sprintf("%s%s?%s", BaseURL, kind.path, params.Encode())
 
 	// Make the request
 	body, err := s.httpClient.Get(ctx, requestURL, apiKey)
@@ -215,7 +321,27 @@ func (s *Server) getQuoteHandler(ctx context.Context, request mcp.CallToolReques
 		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(body)), nil
+	// Reject a quote that pays out to anyone but the requested recipient
+	recipient := toAddress
+	if recipient == "" {
+		recipient = fromAddress
+	}
+	var receiverWarning string
+	if toChainType == ChainTypeEVM && common.IsHexAddress(recipient) {
+		receiverWarning, err = checkQuoteReceiver(body, recipient)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	// Echo the amount conversion so the caller can confirm it, and flag fees out of
+	// proportion to the amount and receivers that could not be verified
+	jsonResponse, err := annotateQuote(body, amountConversion, maxFeePercent, summaryOnly, receiverWarning)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
 
 func (s *Server) getStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -263,67 +389,74 @@ func (s *Server) getChainsHandler(ctx context.Context, request mcp.CallToolReque
 
 	chainTypes := getStringArg(request, "chainTypes")
 
-	// Ensure the chains are loaded
-	chainsCacheMu.RLock()
-	initialized := chainsCacheInitialized
-	chainsCacheMu.RUnlock()
+	opts, err := parsePageOptions(request, defaultChainFields)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	if !initialized {
-		err := s.refreshChainsCache(ctx, apiKey)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
-		}
+	// Ensure the chains are loaded
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
 	}
 
-	chainsCacheMu.RLock()
-	defer chainsCacheMu.RUnlock()
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
 
 	// If no chain types filter is specified, return all chains
-	if chainTypes == "" {
-		jsonData, err := json.Marshal(chainsCache)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("error serializing chain data: %v", err)), nil
+	filteredChains := s.chains.data
+	if chainTypes != "" {
+		// Filter chains by chainTypes
+		chainTypesSlice := strings.Split(chainTypes, ",")
+		filteredChains = ChainData{
+			Chains: []Chain{},
 		}
-		return mcp.NewToolResultText(string(jsonData)), nil
-	}
-
-	// Filter chains by chainTypes
-	chainTypesSlice := strings.Split(chainTypes, ",")
-	filteredChains := ChainData{
-		Chains: []Chain{},
-	}
 
-	for _, chain := range chainsCache.Chains {
-		// Check if the chain matches any of the requested chain types
-		for _, ct := range chainTypesSlice {
-			// This is a simplified check - adjust based on actual data structure
-			if strings.Contains(strings.ToLower(chain.Key), strings.ToLower(strings.TrimSpace(ct))) {
-				filteredChains.Chains = append(filteredChains.Chains, chain)
-				break
+		for _, chain := range s.chains.data.Chains {
+			// Check if the chain matches any of the requested chain types
+			for _, ct := range chainTypesSlice {
+				// This is a simplified check - adjust based on actual data structure
+				if strings.Contains(strings.ToLower(chain.Key), strings.ToLower(strings.TrimSpace(ct))) {
+					filteredChains.Chains = append(filteredChains.Chains, chain)
+					break
+				}
 			}
 		}
-	}
 
-	// If no chains matched the filter, make a direct API call to ensure accurate results
-	if len(filteredChains.Chains) == 0 {
-		// Build the query parameters
-		params := url.Values{}
-		params.Add("chainTypes", chainTypes)
+		// If no chains matched the filter, make a direct API call to ensure accurate results
+		if len(filteredChains.Chains) == 0 {
+			// Build the query parameters
+			params := url.Values{}
+			params.Add("chainTypes", chainTypes)
 
-		// Build the request URL
-		requestURL := fmt.Sprintf("%s/v1/chains?%s", BaseURL, params.Encode())
+			// Build the request URL
+			requestURL := fmt.Sprintf("%s/v1/chains?%s", BaseURL, params.Encode())
 
-		// Make the request
-		body, err := s.httpClient.Get(ctx, requestURL, apiKey)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+			// Make the request
+			body, err := s.httpClient.Get(ctx, requestURL, apiKey)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+			}
+
+			if opts.full {
+				return mcp.NewToolResultText(string(body)), nil
+			}
+			if err := json.Unmarshal(body, &filteredChains); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to parse chain data: %v", err)), nil
+			}
 		}
+	}
 
-		return mcp.NewToolResultText(string(body)), nil
+	var result interface{} = filteredChains
+	if !opts.full {
+		page, err := paginateChains(filteredChains.Chains, opts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result = page
 	}
 
 	// Return the filtered chains
-	jsonData, err := json.Marshal(filteredChains)
+	jsonData, err := json.Marshal(result)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("error serializing filtered chain data: %v", err)), nil
 	}
@@ -340,6 +473,12 @@ func (s *Server) getConnectionsHandler(ctx context.Context, request mcp.CallTool
 	toToken := getStringArg(request, "toToken")
 	chainTypes := getStringArg(request, "chainTypes")
 
+	opts, err := parsePageOptions(request, defaultConnectionFields)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	filter := parseConnectionFilter(request)
+
 	// Build the query parameters
 	params := url.Values{}
 	if fromChain != "" {
@@ -377,7 +516,21 @@ func (s *Server) getConnectionsHandler(ctx context.Context, request mcp.CallTool
 		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(body)), nil
+	if opts.full {
+		return mcp.NewToolResultText(string(body)), nil
+	}
+
+	page, err := paginateConnections(body, opts, filter)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonResponse, err := json.Marshal(page)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
 
 func (s *Server) getToolsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -409,10 +562,27 @@ func (s *Server) getToolsHandler(ctx context.Context, request mcp.CallToolReques
 		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
 	}
 
+	// Keep only key and name for bridges and exchanges
+	filteredResponse, err := summarizeTools(body)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Marshal the filtered response
+	filteredBody, err := json.Marshal(filteredResponse)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to serialize filtered tools response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(filteredBody)), nil
+}
+
+// summarizeTools reduces a /v1/tools response to the key and name of each bridge and exchange
+func summarizeTools(body []byte) (map[string]interface{}, error) {
 	// Parse the response to filter out unnecessary fields
 	var toolsResponse map[string]interface{}
 	if err := json.Unmarshal(body, &toolsResponse); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to parse tools response: %v", err)), nil
+		return nil, fmt.Errorf("failed to parse tools response: %v", err)
 	}
 
 	// Create filtered response with only key and name for bridges and exchanges
@@ -454,13 +624,7 @@ func (s *Server) getToolsHandler(ctx context.Context, request mcp.CallToolReques
 		filteredResponse["exchanges"] = filteredExchanges
 	}
 
-	// Marshal the filtered response
-	filteredBody, err := json.Marshal(filteredResponse)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to serialize filtered tools response: %v", err)), nil
-	}
-
-	return mcp.NewToolResultText(string(filteredBody)), nil
+	return filteredResponse, nil
 }
 
 func (s *Server) getChainByIdHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -481,22 +645,15 @@ func (s *Server) getChainByIdHandler(ctx context.Context, request mcp.CallToolRe
 	}
 
 	// Ensure the chains are loaded
-	chainsCacheMu.RLock()
-	initialized := chainsCacheInitialized
-	chainsCacheMu.RUnlock()
-
-	if !initialized {
-		err := s.refreshChainsCache(ctx, apiKey)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
-		}
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
 	}
 
-	chainsCacheMu.RLock()
-	defer chainsCacheMu.RUnlock()
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
 
 	// Look for the chain by ID
-	for _, chain := range chainsCache.Chains {
+	for _, chain := range s.chains.data.Chains {
 		if chain.ID == id {
 			// Found a match, return the chain data
 			chainData, err := json.Marshal(chain)
@@ -523,25 +680,18 @@ func (s *Server) getChainByNameHandler(ctx context.Context, request mcp.CallTool
 	}
 
 	// Ensure the chains are loaded
-	chainsCacheMu.RLock()
-	initialized := chainsCacheInitialized
-	chainsCacheMu.RUnlock()
-
-	if !initialized {
-		err := s.refreshChainsCache(ctx, apiKey)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
-		}
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
 	}
 
-	chainsCacheMu.RLock()
-	defer chainsCacheMu.RUnlock()
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
 
 	// Convert name to lowercase for case-insensitive matching
 	nameLower := strings.ToLower(name)
 
 	// Look for the chain by name
-	for _, chain := range chainsCache.Chains {
+	for _, chain := range s.chains.data.Chains {
 		// Try matching against name, key, or chain ID as string
 		if strings.ToLower(chain.Name) == nameLower ||
 			strings.ToLower(chain.Key) == nameLower ||
@@ -578,13 +728,17 @@ func (s *Server) getRoutesHandler(ctx context.Context, request mcp.CallToolReque
 	if err := ValidateChainID("toChainId", toChainId); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateTokenAddress("fromTokenAddress", fromTokenAddress); err != nil {
+
+	// Addresses are validated for each chain's type (EVM, SVM or UTXO)
+	fromChainType := s.chainType(ctx, fromChainId, apiKey)
+	toChainType := s.chainType(ctx, toChainId, apiKey)
+	if err := ValidateTokenAddressForChainType("fromTokenAddress", fromTokenAddress, fromChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateTokenAddress("toTokenAddress", toTokenAddress); err != nil {
+	if err := ValidateTokenAddressForChainType("toTokenAddress", toTokenAddress, toChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if err := ValidateAddress("fromAddress", fromAddress); err != nil {
+	if err := ValidateAddressForChainType("fromAddress", fromAddress, fromChainType); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 	if err := ValidateAmount("fromAmount", fromAmount); err != nil {
@@ -598,7 +752,7 @@ func (s *Server) getRoutesHandler(ctx context.Context, request mcp.CallToolReque
 
 	// Validate optional parameters
 	if toAddress != "" {
-		if err := ValidateRecipientAddress("toAddress", toAddress); err != nil {
+		if err := ValidateRecipientAddressForChainType("toAddress", toAddress, toChainType); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 	}
@@ -658,18 +812,45 @@ func (s *Server) getQuoteWithCallsHandler(ctx context.Context, request mcp.CallT
 	fromAmount := getStringArg(request, "fromAmount")
 	contractCalls := getArrayArg(request, "contractCalls")
 
-	// Required parameters check
-	if fromChain == "" || toChain == "" || fromToken == "" || toToken == "" || fromAddress == "" || fromAmount == "" {
-		return mcp.NewToolResultError("required parameters: fromChain, toChain, fromToken, toToken, fromAddress, fromAmount"), nil
+	// Validate required parameters
+	if err := ValidateChainID("fromChain", fromChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateChainID("toChain", toChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	if contractCalls == nil || len(contractCalls) == 0 {
+	// Addresses are validated for each chain's type (EVM, SVM or UTXO)
+	fromChainType := s.chainType(ctx, fromChain, apiKey)
+	toChainType := s.chainType(ctx, toChain, apiKey)
+	if err := ValidateTokenAddressForChainType("fromToken", fromToken, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateTokenAddressForChainType("toToken", toToken, toChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddressForChainType("fromAddress", fromAddress, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAmount("fromAmount", fromAmount); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if len(contractCalls) == 0 {
 		return mcp.NewToolResultError("contractCalls array is required and must not be empty"), nil
 	}
+	if err := validateContractCalls(contractCalls); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	// Get optional parameters
 	slippage := getStringArg(request, "slippage")
 
+	// Validate optional parameters
+	if err := ValidateSlippage(slippage); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Build the request body
 	requestBody := map[string]interface{}{
 		"fromChain":     fromChain,
@@ -703,6 +884,27 @@ func (s *Server) getQuoteWithCallsHandler(ctx context.Context, request mcp.CallT
 	return mcp.NewToolResultText(string(body)), nil
 }
 
+// validateContractCalls checks that every destination call names a target contract and calldata
+func validateContractCalls(contractCalls []interface{}) error {
+	for i, call := range contractCalls {
+		callMap, ok := call.(map[string]interface{})
+		if !ok {
+			return &ValidationError{Field: fmt.Sprintf("contractCalls[%d]", i), Message: "must be an object"}
+		}
+
+		target, _ := callMap["toContractAddress"].(string)
+		if err := ValidateAddress(fmt.Sprintf("contractCalls[%d].toContractAddress", i), target); err != nil {
+			return err
+		}
+
+		callData, _ := callMap["toContractCallData"].(string)
+		if !strings.HasPrefix(callData, "0x") {
+			return &ValidationError{Field: fmt.Sprintf("contractCalls[%d].toContractCallData", i), Message: "must be 0x-prefixed hex calldata"}
+		}
+	}
+	return nil
+}
+
 func (s *Server) getStepTransactionHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	apiKey := APIKeyFromContext(ctx)
 
@@ -761,8 +963,31 @@ func (s *Server) getGasSuggestionHandler(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultError(fmt.Sprintf("chainId must be numeric, got: %s", chainId)), nil
 	}
 
+	// Optional source of the bridge, used to price the recommendation in the source token
+	fromChain := getStringArg(request, "fromChain")
+	fromToken := getStringArg(request, "fromToken")
+
+	if (fromChain == "") != (fromToken == "") {
+		return mcp.NewToolResultError("fromChain and fromToken must be provided together"), nil
+	}
+
+	params := url.Values{}
+	if fromChain != "" {
+		if err := ValidateChainID("fromChain", fromChain); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := ValidateTokenAddress("fromToken", fromToken); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params.Add("fromChain", fromChain)
+		params.Add("fromToken", fromToken)
+	}
+
 	// Build the request URL with path escaping for safety
 	requestURL := fmt.Sprintf("%s/v1/gas/suggestion/%s", BaseURL, url.PathEscape(chainId))
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
 
 	// Make the request
 	body, err := s.httpClient.Get(ctx, requestURL, apiKey)
@@ -788,3 +1013,25 @@ func (s *Server) testApiKeyHandler(ctx context.Context, request mcp.CallToolRequ
 
 	return mcp.NewToolResultText(string(body)), nil
 }
+
+func (s *Server) getRateLimitStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+	responseData := map[string]interface{}{
+		"clientLimiter": s.httpClient.RateLimitStatus(),
+		"apiKeySet":     apiKey != "",
+	}
+	// Report only the quota of the caller's own API key, never another tenant's
+	if quota := s.httpClient.LastQuota(apiKey); quota != nil {
+		responseData["apiQuota"] = quota
+	}
+	if s.toolLimiter != nil {
+		responseData["toolBudgets"] = s.toolLimiter.status(toolLimitSession(ctx), time.Now())
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
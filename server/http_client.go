@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -20,11 +21,18 @@ const (
 	defaultRateLimit  = 200
 	defaultRatePeriod = 2 * time.Hour
 
+	// Rate limit with an API key: 200 requests per minute
+	apiKeyRateLimit  = 200
+	apiKeyRatePeriod = time.Minute
+
 	// Retry configuration
 	maxRetries       = 3
 	baseRetryDelay   = 500 * time.Millisecond
 	maxRetryDelay    = 30 * time.Second
 	retryJitterRatio = 0.3
+
+	// Upper bound on how long a Retry-After header can make a request wait
+	maxRetryAfter = time.Minute
 )
 
 // HTTPClient wraps http.Client with rate limiting and retry logic.
@@ -33,6 +41,33 @@ type HTTPClient struct {
 	client  *http.Client
 	logger  *slog.Logger
 	limiter *rateLimiter
+
+	quotaMu sync.Mutex
+	quotas  map[string]*APIQuota // by API key; "" holds the anonymous quota
+}
+
+// maxTrackedQuotas bounds how many API keys' quotas are remembered at once
+const maxTrackedQuotas = 1024
+
+// APIQuota is the remaining quota reported by the LI.FI API in its rate limit headers
+type APIQuota struct {
+	Limit        int       `json:"limit,omitempty"`
+	Remaining    int       `json:"remaining"`
+	ResetSeconds int       `json:"resetSeconds,omitempty"`
+	ObservedAt   time.Time `json:"observedAt"`
+}
+
+// rateLimitedError is returned for 429 responses and carries the server-provided retry delay
+type rateLimitedError struct {
+	retryAfter time.Duration
+	quota      *APIQuota
+}
+
+func (e *rateLimitedError) Error() string {
+	if e.quota != nil && e.quota.ResetSeconds > 0 {
+		return fmt.Sprintf("rate limited (429): retry after %v, quota resets in %ds", e.retryAfter, e.quota.ResetSeconds)
+	}
+	return fmt.Sprintf("rate limited (429): retry after %v", e.retryAfter)
 }
 
 // rateLimiter implements a simple token bucket rate limiter
@@ -40,31 +75,71 @@ type rateLimiter struct {
 	mu         sync.Mutex
 	tokens     int
 	maxTokens  int
+	period     time.Duration
 	refillRate time.Duration
 	lastRefill time.Time
 }
 
 func newRateLimiter(maxTokens int, period time.Duration) *rateLimiter {
+	// A period shorter than maxTokens nanoseconds would truncate the refill rate to zero
+	refillRate := max(period/time.Duration(maxTokens), time.Nanosecond)
 	return &rateLimiter{
 		tokens:     maxTokens,
 		maxTokens:  maxTokens,
-		refillRate: period / time.Duration(maxTokens),
+		period:     period,
+		refillRate: refillRate,
 		lastRefill: time.Now(),
 	}
 }
 
-func (r *rateLimiter) acquire(ctx context.Context) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	// Refill tokens based on time elapsed
-	now := time.Now()
+// refill adds the tokens accrued since the last refill. Callers must hold r.mu.
+func (r *rateLimiter) refill(now time.Time) {
 	elapsed := now.Sub(r.lastRefill)
 	tokensToAdd := int(elapsed / r.refillRate)
 	if tokensToAdd > 0 {
 		r.tokens = min(r.tokens+tokensToAdd, r.maxTokens)
 		r.lastRefill = now
 	}
+}
+
+// RateLimitStatus is a snapshot of the client-side rate limiter
+type RateLimitStatus struct {
+	Limit                 int     `json:"limit"`
+	PeriodSeconds         float64 `json:"periodSeconds"`
+	Available             int     `json:"available"`
+	RefillIntervalSeconds float64 `json:"refillIntervalSeconds"`
+	NextTokenInSeconds    float64 `json:"nextTokenInSeconds"`
+}
+
+// status returns the current limiter state without consuming a token
+func (r *rateLimiter) status() RateLimitStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.refill(now)
+
+	var nextToken time.Duration
+	if r.tokens < r.maxTokens {
+		nextToken = r.refillRate - now.Sub(r.lastRefill)%r.refillRate
+	}
+
+	return RateLimitStatus{
+		Limit:                 r.maxTokens,
+		PeriodSeconds:         r.period.Seconds(),
+		Available:             r.tokens,
+		RefillIntervalSeconds: r.refillRate.Seconds(),
+		NextTokenInSeconds:    nextToken.Seconds(),
+	}
+}
+
+func (r *rateLimiter) acquire(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Refill tokens based on time elapsed
+	now := time.Now()
+	r.refill(now)
 
 	if r.tokens > 0 {
 		r.tokens--
@@ -88,14 +163,106 @@ func (r *rateLimiter) acquire(ctx context.Context) error {
 // The rate limiter uses default limits; per-request API keys don't change the global limit
 // but are passed through to the LI.FI API which has its own per-key limits.
 func NewHTTPClient(logger *slog.Logger) *HTTPClient {
+	return NewHTTPClientWithRateLimit(logger, defaultRateLimit, defaultRatePeriod)
+}
+
+// NewHTTPClientWithRateLimit creates a new HTTP client that allows limit requests per period.
+// Non-positive values fall back to the defaults.
+func NewHTTPClientWithRateLimit(logger *slog.Logger, limit int, period time.Duration) *HTTPClient {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if limit <= 0 {
+		limit = defaultRateLimit
+	}
+	if period <= 0 {
+		period = defaultRatePeriod
+	}
 
 	return &HTTPClient{
 		client:  &http.Client{Timeout: 30 * time.Second},
 		logger:  logger,
-		limiter: newRateLimiter(defaultRateLimit, defaultRatePeriod),
+		limiter: newRateLimiter(limit, period),
+	}
+}
+
+// DefaultRateLimit returns the LI.FI rate limit for a deployment with or without an API key
+func DefaultRateLimit(hasAPIKey bool) (int, time.Duration) {
+	if hasAPIKey {
+		return apiKeyRateLimit, apiKeyRatePeriod
+	}
+	return defaultRateLimit, defaultRatePeriod
+}
+
+// RateLimitStatus returns the current state of the client-side rate limiter
+func (c *HTTPClient) RateLimitStatus() RateLimitStatus {
+	return c.limiter.status()
+}
+
+// LastQuota returns the quota from the most recent LI.FI response for apiKey that
+// reported one, or nil if none has been seen yet. An empty apiKey returns the quota
+// shared by anonymous requests.
+func (c *HTTPClient) LastQuota(apiKey string) *APIQuota {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+	recorded, ok := c.quotas[apiKey]
+	if !ok {
+		return nil
+	}
+	quota := *recorded
+	return &quota
+}
+
+// recordQuota parses the rate limit headers of a response and remembers them for apiKey
+func (c *HTTPClient) recordQuota(apiKey string, header http.Header) *APIQuota {
+	quota := parseQuotaHeaders(header)
+	if quota == nil {
+		return nil
+	}
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+	if c.quotas == nil {
+		c.quotas = make(map[string]*APIQuota)
+	}
+	if _, ok := c.quotas[apiKey]; !ok && len(c.quotas) >= maxTrackedQuotas {
+		// Forget the key that has gone longest without a response
+		var oldestKey string
+		var oldest time.Time
+		for key, q := range c.quotas {
+			if oldest.IsZero() || q.ObservedAt.Before(oldest) {
+				oldestKey, oldest = key, q.ObservedAt
+			}
+		}
+		delete(c.quotas, oldestKey)
+	}
+	c.quotas[apiKey] = quota
+	return quota
+}
+
+// parseQuotaHeaders reads the RateLimit-* (or legacy X-RateLimit-*) response headers
+func parseQuotaHeaders(header http.Header) *APIQuota {
+	get := func(name string) (int, bool) {
+		for _, key := range []string{"RateLimit-" + name, "X-RateLimit-" + name} {
+			if v := header.Get(key); v != "" {
+				if n, err := strconv.Atoi(v); err == nil {
+					return n, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	remaining, ok := get("Remaining")
+	if !ok {
+		return nil
+	}
+	limit, _ := get("Limit")
+	reset, _ := get("Reset")
+	return &APIQuota{
+		Limit:        limit,
+		Remaining:    remaining,
+		ResetSeconds: reset,
+		ObservedAt:   time.Now(),
 	}
 }
 
@@ -131,8 +298,12 @@ func (c *HTTPClient) doWithRetry(ctx context.Context, method, requestURL string,
 			break
 		}
 
-		// Calculate backoff with jitter
+		// Wait as long as the server asked on 429, otherwise back off with jitter
 		delay := c.calculateBackoff(attempt)
+		var rateLimited *rateLimitedError
+		if errors.As(err, &rateLimited) {
+			delay = rateLimited.retryAfter
+		}
 		c.logger.Debug("Retrying request",
 			"attempt", attempt+1,
 			"max_retries", maxRetries,
@@ -170,6 +341,10 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, b
 
 	resp, err := c.client.Do(req)
 	if err != nil {
+		// The caller gave up; retrying would only delay reporting it
+		if ctx.Err() != nil {
+			return nil, ctx.Err(), false
+		}
 		// Network errors are retryable
 		return nil, err, true
 	}
@@ -180,6 +355,8 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, b
 		return nil, err, true
 	}
 
+	quota := c.recordQuota(apiKey, resp.Header)
+
 	// Handle rate limiting (429)
 	if resp.StatusCode == http.StatusTooManyRequests {
 		retryAfter := c.parseRetryAfter(resp.Header.Get("Retry-After"))
@@ -187,7 +364,7 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, requestURL string, b
 			"retry_after", retryAfter,
 			"url", requestURL,
 		)
-		return nil, fmt.Errorf("rate limited (429): retry after %v", retryAfter), true
+		return nil, &rateLimitedError{retryAfter: retryAfter, quota: quota}, true
 	}
 
 	// Server errors are retryable
@@ -217,20 +394,24 @@ func (c *HTTPClient) calculateBackoff(attempt int) time.Duration {
 	return delay
 }
 
+// parseRetryAfter parses a Retry-After header as seconds or an HTTP date,
+// capped at maxRetryAfter
 func (c *HTTPClient) parseRetryAfter(header string) time.Duration {
 	if header == "" {
 		return baseRetryDelay
 	}
 
-	// Try to parse as seconds
+	delay := baseRetryDelay
 	if seconds, err := strconv.Atoi(header); err == nil {
-		return time.Duration(seconds) * time.Second
+		// Try to parse as seconds
+		delay = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(header); err == nil {
+		// Try to parse as HTTP date
+		delay = time.Until(t)
 	}
 
-	// Try to parse as HTTP date (RFC 1123)
-	if t, err := time.Parse(time.RFC1123, header); err == nil {
-		return time.Until(t)
+	if delay <= 0 {
+		return baseRetryDelay
 	}
-
-	return baseRetryDelay
+	return min(delay, maxRetryAfter)
 }
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestHTTPClient() *HTTPClient {
+	return NewHTTPClientWithRateLimit(slog.New(slog.NewTextHandler(io.Discard, nil)), 1000, time.Second)
+}
+
+func TestHTTPClientRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	body, err := newTestHTTPClient().Get(context.Background(), srv.URL, "")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("unexpected body %s", body)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("got %d requests, want 2", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %s, before the 1s Retry-After", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	c := newTestHTTPClient()
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", baseRetryDelay},
+		{"2", 2 * time.Second},
+		{"0", baseRetryDelay},
+		{"-5", baseRetryDelay},
+		{"not a delay", baseRetryDelay},
+		{"3600", maxRetryAfter},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), maxRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := c.parseRetryAfter(tt.header); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPClientRetryCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   int32
+	}{
+		{"rate limited", http.StatusTooManyRequests, maxRetries + 1},
+		{"server error", http.StatusBadGateway, maxRetries + 1},
+		{"client error", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				// Retry-After: 0 falls back to the base delay, keeping the test short
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			_, err := newTestHTTPClient().Get(context.Background(), srv.URL, "")
+			if err == nil {
+				t.Fatal("Get succeeded, want an error")
+			}
+			var rateLimited *rateLimitedError
+			if tt.status == http.StatusTooManyRequests && !errors.As(err, &rateLimited) {
+				t.Fatalf("got %v, want a rateLimitedError", err)
+			}
+			if calls.Load() != tt.want {
+				t.Fatalf("got %d requests, want %d", calls.Load(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientAPIKeyHeader(t *testing.T) {
+	var gotKey, gotContentType atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey.Store(r.Header.Get(apiKeyHeader))
+		gotContentType.Store(r.Header.Get("Content-Type"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newTestHTTPClient()
+	if _, err := c.Post(context.Background(), srv.URL, []byte(`{}`), "secret"); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if gotKey.Load() != "secret" {
+		t.Fatalf("API key header = %q, want %q", gotKey.Load(), "secret")
+	}
+	if gotContentType.Load() != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", gotContentType.Load())
+	}
+
+	if _, err := c.Get(context.Background(), srv.URL, ""); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gotKey.Load() != "" {
+		t.Fatalf("anonymous request sent API key %q", gotKey.Load())
+	}
+}
+
+func TestHTTPClientRecordsQuota(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Limit", "200")
+		w.Header().Set("RateLimit-Remaining", "150")
+		w.Header().Set("RateLimit-Reset", "42")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newTestHTTPClient()
+	if _, err := c.Get(context.Background(), srv.URL, "secret"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	quota := c.LastQuota("secret")
+	if quota == nil || quota.Limit != 200 || quota.Remaining != 150 || quota.ResetSeconds != 42 {
+		t.Fatalf("unexpected quota %+v", quota)
+	}
+	if other := c.LastQuota("other"); other != nil {
+		t.Fatalf("quota for secret reported to another key: %+v", other)
+	}
+	if anonymous := c.LastQuota(""); anonymous != nil {
+		t.Fatalf("quota for secret reported to anonymous callers: %+v", anonymous)
+	}
+}
+
+func TestRateLimiterShortPeriod(t *testing.T) {
+	// 1000 tokens per microsecond would truncate the refill interval to zero
+	r := newRateLimiter(1000, time.Microsecond)
+	if r.refillRate <= 0 {
+		t.Fatalf("refill interval %s, want a positive interval", r.refillRate)
+	}
+	if err := r.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if status := r.status(); status.RefillIntervalSeconds <= 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// maxLoggedArgsLength caps the size of the arguments included in tool invocation logs
+const maxLoggedArgsLength = 512
+
+// logToolCalls logs every tool invocation with its name, duration, truncated
+// arguments and outcome
+func (s *Server) logToolCalls(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		outcome := "success"
+		switch {
+		case err != nil:
+			outcome = "error"
+		case result != nil && result.IsError:
+			outcome = "tool_error"
+		}
+
+		attrs := []any{
+			"tool", request.Params.Name,
+			"durationMs", time.Since(start).Milliseconds(),
+			"args", truncatedArgs(request.Params.Arguments),
+			"outcome", outcome,
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		} else if result != nil && result.IsError {
+			attrs = append(attrs, "error", truncate(resultText(result), maxLoggedArgsLength))
+		}
+		s.logger.Info("Tool call", attrs...)
+
+		return result, err
+	}
+}
+
+// truncatedArgs serializes tool arguments for logging, capped at maxLoggedArgsLength
+func truncatedArgs(args any) string {
+	if args == nil {
+		return ""
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "<unserializable>"
+	}
+	return truncate(string(data), maxLoggedArgsLength)
+}
+
+// truncate shortens s to at most n bytes, marking the cut
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "...(truncated)"
+}
+
+// resultText returns the concatenated text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, c := range result.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			text += tc.Text
+		}
+	}
+	return text
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Multicall3Address is the deterministic Multicall3 deployment address, identical on
+// every chain it has been deployed to (https://www.multicall3.com)
+const Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// Multicall3ABI contains the subset of Multicall3 used for batched reads
+const Multicall3ABI = `[
+	{
+		"inputs": [
+			{
+				"components": [
+					{"name": "target", "type": "address"},
+					{"name": "allowFailure", "type": "bool"},
+					{"name": "callData", "type": "bytes"}
+				],
+				"name": "calls",
+				"type": "tuple[]"
+			}
+		],
+		"name": "aggregate3",
+		"outputs": [
+			{
+				"components": [
+					{"name": "success", "type": "bool"},
+					{"name": "returnData", "type": "bytes"}
+				],
+				"name": "returnData",
+				"type": "tuple[]"
+			}
+		],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "addr", "type": "address"}],
+		"name": "getEthBalance",
+		"outputs": [{"name": "balance", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// multicallCall is a single call in a Multicall3 aggregate3 batch
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicallResult is the result of a single call in a Multicall3 aggregate3 batch
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// multicall executes calls in a single eth_call through Multicall3. Individual calls
+// are allowed to fail; check Success on each result.
+func multicall(ctx context.Context, client *ethclient.Client, calls []multicallCall) ([]multicallResult, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(Multicall3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Multicall3 ABI: %v", err)
+	}
+
+	data, err := parsedABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3 data: %v", err)
+	}
+
+	multicallAddr := common.HexToAddress(Multicall3Address)
+	output, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &multicallAddr,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Multicall3: %v", err)
+	}
+
+	var results []multicallResult
+	if err := parsedABI.UnpackIntoInterface(&results, "aggregate3", output); err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 result: %v", err)
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("Multicall3 returned %d results for %d calls", len(results), len(calls))
+	}
+
+	return results, nil
+}
+
+// multicallGetEthBalanceCall builds a Multicall3 getEthBalance call for the native balance of addr
+func multicallGetEthBalanceCall(addr common.Address) (multicallCall, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(Multicall3ABI))
+	if err != nil {
+		return multicallCall{}, fmt.Errorf("failed to parse Multicall3 ABI: %v", err)
+	}
+
+	data, err := parsedABI.Pack("getEthBalance", addr)
+	if err != nil {
+		return multicallCall{}, fmt.Errorf("failed to pack getEthBalance data: %v", err)
+	}
+
+	return multicallCall{
+		Target:       common.HexToAddress(Multicall3Address),
+		AllowFailure: true,
+		CallData:     data,
+	}, nil
+}
+
+// decodeUint256 decodes a single ABI-encoded uint256 return value
+func decodeUint256(data []byte) (*big.Int, bool) {
+	if len(data) != 32 {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(data), true
+}
@@ -0,0 +1,496 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// NFTABI covers the ERC-165 and ERC-721 functions used by the NFT tools, plus the
+// ERC-1155 uri function
+const NFTABI = `[
+	{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceId","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]}
+]`
+
+// ERC1155ABI holds the ERC-1155 functions whose names clash with ERC-721 ones, so
+// they are packed from a separate ABI
+const ERC1155ABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]}
+]`
+
+const (
+	// NFT standards as reported in tool responses
+	standardERC721  = "ERC721"
+	standardERC1155 = "ERC1155"
+
+	// ipfsGateway resolves ipfs:// metadata URIs
+	ipfsGateway = "https://ipfs.io/ipfs/"
+
+	// maxMetadataSize caps the size of fetched token metadata
+	maxMetadataSize = 1 << 20
+)
+
+var (
+	// ERC-165 interface IDs
+	interfaceIDERC721  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	interfaceIDERC1155 = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+)
+
+// nftContract bundles the parsed ABIs and the detected standard of an NFT contract
+type nftContract struct {
+	address  common.Address
+	standard string
+	abi      abi.ABI
+	abi1155  abi.ABI
+}
+
+// detectNFTContract determines via ERC-165 whether address is an ERC-721 or ERC-1155 contract
+func detectNFTContract(ctx context.Context, client *ethclient.Client, address common.Address) (*nftContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(NFTABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse NFT ABI: %v", err)
+	}
+	parsed1155, err := abi.JSON(strings.NewReader(ERC1155ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC1155 ABI: %v", err)
+	}
+	contract := &nftContract{address: address, abi: parsed, abi1155: parsed1155}
+
+	supports := func(id [4]byte) bool {
+		data, err := parsed.Pack("supportsInterface", id)
+		if err != nil {
+			return false
+		}
+		out, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+		if err != nil || len(out) != 32 {
+			return false
+		}
+		return out[31] == 1
+	}
+
+	switch {
+	case supports(interfaceIDERC721):
+		contract.standard = standardERC721
+	case supports(interfaceIDERC1155):
+		contract.standard = standardERC1155
+	default:
+		return nil, fmt.Errorf("%s does not report ERC-721 or ERC-1155 support via ERC-165", address.Hex())
+	}
+	return contract, nil
+}
+
+// call packs and executes a view call and unpacks its single return value
+func (c *nftContract) call(ctx context.Context, client *ethclient.Client, contractABI abi.ABI, method string, args ...interface{}) (interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s data: %v", method, err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &c.address, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %v", method, err)
+	}
+	values, err := contractABI.Unpack(method, out)
+	if err != nil || len(values) == 0 {
+		return nil, fmt.Errorf("failed to unpack %s result: %v", method, err)
+	}
+	return values[0], nil
+}
+
+// balance returns the owner's balance: the number of tokens held for ERC-721, or the
+// amount of tokenID held for ERC-1155
+func (c *nftContract) balance(ctx context.Context, client *ethclient.Client, owner common.Address, tokenID *big.Int) (*big.Int, error) {
+	var v interface{}
+	var err error
+	if c.standard == standardERC1155 {
+		v, err = c.call(ctx, client, c.abi1155, "balanceOf", owner, tokenID)
+	} else {
+		v, err = c.call(ctx, client, c.abi, "balanceOf", owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.(*big.Int), nil
+}
+
+// tokenURI returns the metadata URI of a token, with the ERC-1155 {id} placeholder substituted
+func (c *nftContract) tokenURI(ctx context.Context, client *ethclient.Client, tokenID *big.Int) (string, error) {
+	if c.standard == standardERC1155 {
+		v, err := c.call(ctx, client, c.abi, "uri", tokenID)
+		if err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(v.(string), "{id}", fmt.Sprintf("%064x", tokenID)), nil
+	}
+	v, err := c.call(ctx, client, c.abi, "tokenURI", tokenID)
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// fetchNFTMetadata resolves a token URI (http(s), ipfs:// or data:) to its JSON metadata
+func (s *Server) fetchNFTMetadata(ctx context.Context, uri string) (map[string]interface{}, error) {
+	var body []byte
+	switch {
+	case strings.HasPrefix(uri, "data:"):
+		header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+		if !ok {
+			return nil, fmt.Errorf("malformed data URI")
+		}
+		if strings.HasSuffix(header, ";base64") {
+			decoded, err := base64.StdEncoding.DecodeString(payload)
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 data URI: %v", err)
+			}
+			body = decoded
+		} else {
+			unescaped, err := url.PathUnescape(payload)
+			if err != nil {
+				return nil, fmt.Errorf("invalid data URI: %v", err)
+			}
+			body = []byte(unescaped)
+		}
+	default:
+		fetchURL := uri
+		if strings.HasPrefix(uri, "ipfs://") {
+			fetchURL = ipfsGateway + strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		}
+		if !strings.HasPrefix(fetchURL, "https://") && !strings.HasPrefix(fetchURL, "http://") {
+			return nil, fmt.Errorf("unsupported metadata URI scheme: %s", uri)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.externalHTTP.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("metadata request returned HTTP %d", resp.StatusCode)
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("metadata is not a JSON object: %v", err)
+	}
+	return metadata, nil
+}
+
+// parseTokenID parses a decimal or 0x-prefixed hex token ID
+func parseTokenID(field, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, &ValidationError{Field: field, Message: "token ID is required"}
+	}
+	id, err := parseQuantity(value)
+	if err != nil {
+		return nil, &ValidationError{Field: field, Message: err.Error()}
+	}
+	return id, nil
+}
+
+func (s *Server) getNftBalanceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	contractAddress := getStringArg(request, "contractAddress")
+	ownerAddress := getStringArg(request, "ownerAddress")
+	tokenIDArg := getStringArg(request, "tokenId")
+
+	if err := ValidateAddress("contractAddress", contractAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddress("ownerAddress", ownerAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	contract, err := detectNFTContract(ctx, client, common.HexToAddress(contractAddress))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var tokenID *big.Int
+	if tokenIDArg != "" || contract.standard == standardERC1155 {
+		if tokenID, err = parseTokenID("tokenId", tokenIDArg); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%v (required for ERC-1155 contracts)", err)), nil
+		}
+	}
+
+	owner := common.HexToAddress(ownerAddress)
+	responseData := map[string]interface{}{
+		"contractAddress": contract.address.Hex(),
+		"ownerAddress":    owner.Hex(),
+		"standard":        contract.standard,
+	}
+
+	if contract.standard == standardERC721 && tokenID != nil {
+		// For a specific ERC-721 token the balance is whether the owner holds it
+		v, err := contract.call(ctx, client, contract.abi, "ownerOf", tokenID)
+		if err != nil {
+			return revertErrorResult("failed to get token owner", err), nil
+		}
+		balance := int64(0)
+		if v.(common.Address) == owner {
+			balance = 1
+		}
+		responseData["tokenId"] = tokenID.String()
+		responseData["balance"] = fmt.Sprint(balance)
+	} else {
+		balance, err := contract.balance(ctx, client, owner, tokenID)
+		if err != nil {
+			return revertErrorResult("failed to get NFT balance", err), nil
+		}
+		if tokenID != nil {
+			responseData["tokenId"] = tokenID.String()
+		}
+		responseData["balance"] = balance.String()
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) getNftOwnerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	contractAddress := getStringArg(request, "contractAddress")
+	includeMetadata := mcp.ParseBoolean(request, "includeMetadata", true)
+
+	if err := ValidateAddress("contractAddress", contractAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tokenID, err := parseTokenID("tokenId", getStringArg(request, "tokenId"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	contract, err := detectNFTContract(ctx, client, common.HexToAddress(contractAddress))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	responseData := map[string]interface{}{
+		"contractAddress": contract.address.Hex(),
+		"tokenId":         tokenID.String(),
+		"standard":        contract.standard,
+	}
+	if name, err := contract.call(ctx, client, contract.abi, "name"); err == nil {
+		responseData["collectionName"] = name
+	}
+
+	if contract.standard == standardERC721 {
+		v, err := contract.call(ctx, client, contract.abi, "ownerOf", tokenID)
+		if err != nil {
+			return revertErrorResult("failed to get token owner", err), nil
+		}
+		responseData["owner"] = v.(common.Address).Hex()
+	} else {
+		responseData["note"] = "ERC-1155 tokens can have many holders and the standard has no ownerOf; use get-nft-balance to check a specific address."
+	}
+
+	if uri, err := contract.tokenURI(ctx, client, tokenID); err == nil && uri != "" {
+		responseData["tokenURI"] = uri
+		if includeMetadata {
+			metadata, err := s.fetchNFTMetadata(ctx, uri)
+			if err != nil {
+				responseData["metadataError"] = err.Error()
+			} else {
+				responseData["metadata"] = metadata
+			}
+		}
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) transferNftHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	contractAddress := getStringArg(request, "contractAddress")
+	fromAddress := getStringArg(request, "fromAddress")
+	toAddress := getStringArg(request, "toAddress")
+	amountArg := getStringArg(request, "amount")
+
+	if err := ValidateAddress("contractAddress", contractAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddress("fromAddress", fromAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateRecipientAddress("toAddress", toAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tokenID, err := parseTokenID("tokenId", getStringArg(request, "tokenId"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	contract, err := detectNFTContract(ctx, client, common.HexToAddress(contractAddress))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	from := common.HexToAddress(fromAddress)
+	to := common.HexToAddress(toAddress)
+
+	// Check the sender actually holds what it is about to send
+	var data []byte
+	amount := big.NewInt(1)
+	if contract.standard == standardERC1155 {
+		if amountArg != "" {
+			if err := ValidateAmount("amount", amountArg); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			amount, _ = new(big.Int).SetString(amountArg, 10)
+		}
+		balance, err := contract.balance(ctx, client, from, tokenID)
+		if err != nil {
+			return revertErrorResult("failed to get NFT balance", err), nil
+		}
+		if balance.Cmp(amount) < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("fromAddress holds %s of token %s, less than the %s to transfer", balance, tokenID, amount)), nil
+		}
+		data, err = contract.abi1155.Pack("safeTransferFrom", from, to, tokenID, amount, []byte{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pack safeTransferFrom data: %v", err)), nil
+		}
+	} else {
+		if amountArg != "" && amountArg != "1" {
+			return mcp.NewToolResultError("amount must be 1 (or omitted) for ERC-721 tokens"), nil
+		}
+		v, err := contract.call(ctx, client, contract.abi, "ownerOf", tokenID)
+		if err != nil {
+			return revertErrorResult("failed to get token owner", err), nil
+		}
+		if owner := v.(common.Address); owner != from {
+			return mcp.NewToolResultError(fmt.Sprintf("token %s is owned by %s, not fromAddress", tokenID, owner.Hex())), nil
+		}
+		data, err = contract.abi.Pack("safeTransferFrom", from, to, tokenID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to pack safeTransferFrom data: %v", err)), nil
+		}
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	txRequest := map[string]interface{}{
+		"from":    from.Hex(),
+		"to":      contract.address.Hex(),
+		"data":    hexutil.Encode(data),
+		"value":   "0x0",
+		"chainId": chainID.Int64(),
+	}
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &contract.address, Data: data})
+	if err != nil {
+		// safeTransferFrom reverts if the recipient is a contract that does not accept NFTs
+		return revertErrorResult("transfer would fail", err), nil
+	}
+	txRequest["gasLimit"] = hexutil.EncodeUint64(gas)
+
+	responseData := map[string]interface{}{
+		"standard":           contract.standard,
+		"contractAddress":    contract.address.Hex(),
+		"tokenId":            tokenID.String(),
+		"amount":             amount.String(),
+		"fromAddress":        from.Hex(),
+		"toAddress":          to.Hex(),
+		"transactionRequest": txRequest,
+		"note":               "Sign and broadcast transactionRequest with the fromAddress wallet.",
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
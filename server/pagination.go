@@ -0,0 +1,255 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// get-tokens, get-chains and get-connections can return megabytes of JSON. By default
+// they return one page of items reduced to a few summary fields; limit/offset page
+// through the rest, fields picks other fields and full=true returns the raw response.
+
+const (
+	// defaultPageLimit is the number of items returned when no limit is given
+	defaultPageLimit = 50
+
+	// maxPageLimit caps the limit argument
+	maxPageLimit = 1000
+)
+
+var (
+	// Summary fields returned by default for each listing
+	defaultTokenFields      = []string{"address", "symbol", "decimals", "name", "priceUSD"}
+	defaultChainFields      = []string{"id", "key", "name", "chainType", "nativeToken"}
+	defaultConnectionFields = []string{"address", "symbol", "decimals"}
+)
+
+// pageOptions are the pagination and projection arguments shared by the listing tools
+type pageOptions struct {
+	limit  int
+	offset int
+	fields []string
+	full   bool
+}
+
+// parsePageOptions reads limit, offset, fields and full from the request
+func parsePageOptions(request mcp.CallToolRequest, defaultFields []string) (pageOptions, error) {
+	opts := pageOptions{
+		limit:  mcp.ParseInt(request, "limit", defaultPageLimit),
+		offset: mcp.ParseInt(request, "offset", 0),
+		fields: defaultFields,
+		full:   mcp.ParseBoolean(request, "full", false),
+	}
+	if opts.limit < 1 || opts.limit > maxPageLimit {
+		return opts, &ValidationError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxPageLimit)}
+	}
+	if opts.offset < 0 {
+		return opts, &ValidationError{Field: "offset", Message: "must not be negative"}
+	}
+	if fields := getStringArg(request, "fields"); fields != "" {
+		opts.fields = nil
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				opts.fields = append(opts.fields, f)
+			}
+		}
+	}
+	return opts, nil
+}
+
+// window returns the [start, end) bounds of the page within total items
+func (o pageOptions) window(total int) (int, int) {
+	start := min(o.offset, total)
+	return start, min(start+o.limit, total)
+}
+
+// meta describes the returned page so the caller knows how to fetch the next one
+func (o pageOptions) meta(total, returned int) map[string]interface{} {
+	meta := map[string]interface{}{
+		"total":    total,
+		"offset":   o.offset,
+		"limit":    o.limit,
+		"returned": returned,
+		"fields":   o.fields,
+	}
+	if o.offset+returned < total {
+		meta["nextOffset"] = o.offset + returned
+	}
+	return meta
+}
+
+// project keeps only the given top-level fields of each item
+func project(items []map[string]interface{}, fields []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		p := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if v, ok := item[f]; ok {
+				p[f] = v
+			}
+		}
+		projected[i] = p
+	}
+	return projected
+}
+
+// paginateTokens pages through a /v1/tokens response across all of its chains, in
+// the order the chains were requested (or ascending chain ID order otherwise)
+func paginateTokens(body []byte, chainOrder []string, opts pageOptions) (map[string]interface{}, error) {
+	var tokenList struct {
+		Tokens map[string][]map[string]interface{} `json:"tokens"`
+	}
+	if err := json.Unmarshal(body, &tokenList); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens response: %v", err)
+	}
+
+	if len(chainOrder) == 0 {
+		for chainID := range tokenList.Tokens {
+			chainOrder = append(chainOrder, chainID)
+		}
+		sort.Slice(chainOrder, func(i, j int) bool {
+			a, _ := strconv.Atoi(chainOrder[i])
+			b, _ := strconv.Atoi(chainOrder[j])
+			return a < b
+		})
+	}
+
+	// Flatten, keeping track of which chain each token belongs to
+	type chainToken struct {
+		chainID string
+		token   map[string]interface{}
+	}
+	var all []chainToken
+	for _, chainID := range chainOrder {
+		for _, t := range tokenList.Tokens[chainID] {
+			all = append(all, chainToken{chainID: chainID, token: t})
+		}
+	}
+
+	start, end := opts.window(len(all))
+	page := make(map[string][]map[string]interface{})
+	for _, ct := range all[start:end] {
+		page[ct.chainID] = append(page[ct.chainID], project([]map[string]interface{}{ct.token}, opts.fields)...)
+	}
+
+	return map[string]interface{}{
+		"tokens": page,
+		"page":   opts.meta(len(all), end-start),
+	}, nil
+}
+
+// paginateChains pages through a list of chains
+func paginateChains(chains []Chain, opts pageOptions) (map[string]interface{}, error) {
+	data, err := json.Marshal(chains)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing chain data: %v", err)
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("error serializing chain data: %v", err)
+	}
+
+	start, end := opts.window(len(items))
+	return map[string]interface{}{
+		"chains": project(items[start:end], opts.fields),
+		"page":   opts.meta(len(items), end-start),
+	}, nil
+}
+
+// connectionFilter narrows a /v1/connections response before it is paged
+type connectionFilter struct {
+	// symbols keeps only tokens with one of these (upper-cased) symbols
+	symbols map[string]bool
+	// summaryOnly replaces the token lists with their counts
+	summaryOnly bool
+}
+
+// parseConnectionFilter reads the symbols and summaryOnly arguments of get-connections
+func parseConnectionFilter(request mcp.CallToolRequest) connectionFilter {
+	filter := connectionFilter{summaryOnly: mcp.ParseBoolean(request, "summaryOnly", false)}
+	for _, symbol := range strings.Split(getStringArg(request, "symbols"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			if filter.symbols == nil {
+				filter.symbols = map[string]bool{}
+			}
+			filter.symbols[symbol] = true
+		}
+	}
+	return filter
+}
+
+// tokens returns the tokens matching the symbol filter
+func (f connectionFilter) tokens(tokens []map[string]interface{}) []map[string]interface{} {
+	if f.symbols == nil {
+		return tokens
+	}
+	matched := make([]map[string]interface{}, 0)
+	for _, t := range tokens {
+		if symbol, _ := t["symbol"].(string); f.symbols[strings.ToUpper(symbol)] {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// paginateConnections pages through the fromTokens and toTokens of each connection in a
+// /v1/connections response, which is where its size comes from
+func paginateConnections(body []byte, opts pageOptions, filter connectionFilter) (map[string]interface{}, error) {
+	var response struct {
+		Connections []struct {
+			FromChainID int                      `json:"fromChainId"`
+			ToChainID   int                      `json:"toChainId"`
+			FromTokens  []map[string]interface{} `json:"fromTokens"`
+			ToTokens    []map[string]interface{} `json:"toTokens"`
+		} `json:"connections"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse connections response: %v", err)
+	}
+
+	connections := make([]map[string]interface{}, 0, len(response.Connections))
+	totalFrom, totalTo := 0, 0
+	for _, c := range response.Connections {
+		c.FromTokens, c.ToTokens = filter.tokens(c.FromTokens), filter.tokens(c.ToTokens)
+		if filter.symbols != nil && (len(c.FromTokens) == 0 || len(c.ToTokens) == 0) {
+			continue
+		}
+		totalFrom += len(c.FromTokens)
+		totalTo += len(c.ToTokens)
+
+		if filter.summaryOnly {
+			connections = append(connections, map[string]interface{}{
+				"fromChainId":    c.FromChainID,
+				"toChainId":      c.ToChainID,
+				"fromTokenCount": len(c.FromTokens),
+				"toTokenCount":   len(c.ToTokens),
+			})
+			continue
+		}
+
+		fromStart, fromEnd := opts.window(len(c.FromTokens))
+		toStart, toEnd := opts.window(len(c.ToTokens))
+		connections = append(connections, map[string]interface{}{
+			"fromChainId":    c.FromChainID,
+			"toChainId":      c.ToChainID,
+			"fromTokens":     project(c.FromTokens[fromStart:fromEnd], opts.fields),
+			"toTokens":       project(c.ToTokens[toStart:toEnd], opts.fields),
+			"fromTokensPage": opts.meta(len(c.FromTokens), fromEnd-fromStart),
+			"toTokensPage":   opts.meta(len(c.ToTokens), toEnd-toStart),
+		})
+	}
+
+	return map[string]interface{}{
+		"connections": connections,
+		"summary": map[string]interface{}{
+			"connections": len(connections),
+			"fromTokens":  totalFrom,
+			"toTokens":    totalTo,
+		},
+	}, nil
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	// defaultPortfolioChains are scanned when no chains are given:
+	// Ethereum, Optimism, BSC, Polygon, Base, Arbitrum, Avalanche
+	defaultPortfolioChains = []string{"1", "10", "56", "137", "8453", "42161", "43114"}
+
+	// majorTokenSymbols are the ERC20 tokens looked up on every scanned chain
+	majorTokenSymbols = []string{"USDC", "USDT", "DAI", "WETH", "WBTC"}
+)
+
+const (
+	// maxPortfolioChains caps the fan-out of a single get-wallet-portfolio call
+	maxPortfolioChains = 20
+)
+
+// portfolioHolding is a single non-zero balance in a wallet portfolio
+type portfolioHolding struct {
+	Address   string  `json:"address"`
+	Symbol    string  `json:"symbol"`
+	Decimals  int     `json:"decimals"`
+	Balance   string  `json:"balance"`
+	Formatted string  `json:"formatted"`
+	PriceUSD  string  `json:"priceUSD,omitempty"`
+	ValueUSD  float64 `json:"valueUSD"`
+}
+
+// portfolioChain holds the balances found on one chain
+type portfolioChain struct {
+	ChainID  string             `json:"chainId"`
+	Holdings []portfolioHolding `json:"holdings"`
+	ValueUSD float64            `json:"valueUSD"`
+	Error    string             `json:"error,omitempty"`
+}
+
+func (s *Server) getWalletPortfolioHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	address := getStringArg(request, "address")
+	chainsArg := getStringArg(request, "chains")
+
+	if err := ValidateAddress("address", address); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	chainIDs := defaultPortfolioChains
+	if chainsArg != "" {
+		chainIDs = nil
+		for _, c := range strings.Split(chainsArg, ",") {
+			c = strings.TrimSpace(c)
+			if err := ValidateChainID("chains", c); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			chainIDs = append(chainIDs, c)
+		}
+	}
+	if len(chainIDs) > maxPortfolioChains {
+		return mcp.NewToolResultError(fmt.Sprintf("at most %d chains can be scanned per call", maxPortfolioChains)), nil
+	}
+
+	// One token list request covers native token metadata, major tokens and prices for all chains
+	tokensByChain, err := s.fetchTokenList(ctx, chainIDs, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	wallet := common.HexToAddress(address)
+	results := make([]portfolioChain, len(chainIDs))
+
+	var wg sync.WaitGroup
+	for i, chainID := range chainIDs {
+		wg.Add(1)
+		go func(i int, chainID string) {
+			defer wg.Done()
+			results[i] = s.scanChainPortfolio(ctx, chainID, wallet, tokensByChain[chainID], apiKey)
+		}(i, chainID)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("request canceled: %v", ctx.Err())), nil
+	}
+
+	total := 0.0
+	for _, r := range results {
+		total += r.ValueUSD
+	}
+
+	responseData := map[string]interface{}{
+		"address":       checksumAddress(address),
+		"chains":        results,
+		"totalValueUSD": total,
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// scanChainPortfolio reads the native and major ERC20 balances of wallet on one chain
+// in a single Multicall3 round-trip. Errors are reported in the result, not returned,
+// so one unreachable chain does not fail the whole portfolio.
+func (s *Server) scanChainPortfolio(ctx context.Context, chainID string, wallet common.Address, tokens []TokenListEntry, apiKey string) portfolioChain {
+	result := portfolioChain{ChainID: chainID, Holdings: []portfolioHolding{}}
+
+	rpcUrl, err := s.resolveRpcUrl(ctx, chainID, "", apiKey)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer release()
+
+	// Pick the native token and the major tokens from the LI.FI list
+	var candidates []TokenListEntry
+	for _, t := range tokens {
+		if strings.EqualFold(t.Address, ZeroAddress) {
+			candidates = append([]TokenListEntry{t}, candidates...)
+			continue
+		}
+		for _, symbol := range majorTokenSymbols {
+			if strings.EqualFold(t.Symbol, symbol) {
+				candidates = append(candidates, t)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		result.Error = "no known tokens for this chain"
+		return result
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to parse ERC20 ABI: %v", err)
+		return result
+	}
+
+	calls := make([]multicallCall, 0, len(candidates))
+	for _, t := range candidates {
+		if strings.EqualFold(t.Address, ZeroAddress) {
+			call, err := multicallGetEthBalanceCall(wallet)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			calls = append(calls, call)
+			continue
+		}
+
+		data, err := erc20ABI.Pack("balanceOf", wallet)
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to pack balanceOf data: %v", err)
+			return result
+		}
+		calls = append(calls, multicallCall{
+			Target:       common.HexToAddress(t.Address),
+			AllowFailure: true,
+			CallData:     data,
+		})
+	}
+
+	callResults, err := multicall(ctx, client, calls)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	for i, r := range callResults {
+		if !r.Success {
+			continue
+		}
+		balance, ok := decodeUint256(r.ReturnData)
+		if !ok || balance.Sign() == 0 {
+			continue
+		}
+
+		t := candidates[i]
+		value := usdValue(balance, t.Decimals, t.PriceUSD)
+		result.Holdings = append(result.Holdings, portfolioHolding{
+			Address:   checksumAddress(t.Address),
+			Symbol:    t.Symbol,
+			Decimals:  t.Decimals,
+			Balance:   balance.String(),
+			Formatted: formatUnits(balance, t.Decimals),
+			PriceUSD:  t.PriceUSD,
+			ValueUSD:  value,
+		})
+		result.ValueUSD += value
+	}
+
+	// Largest holdings first
+	sort.SliceStable(result.Holdings, func(a, b int) bool {
+		return result.Holdings[a].ValueUSD > result.Holdings[b].ValueUSD
+	})
+
+	return result
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxImpactTranches caps the number of quotes requested by one analyze-price-impact call
+	maxImpactTranches = 8
+
+	// defaultSplitThresholdPercent is the full-size price impact above which splitting is recommended
+	defaultSplitThresholdPercent = 1.0
+)
+
+// defaultImpactFractions are the tranche sizes quoted when none are given, as fractions of fromAmount
+var defaultImpactFractions = []float64{0.01, 0.1, 0.5, 1}
+
+// impactTranche is the quote result for one fraction of the requested amount
+type impactTranche struct {
+	Fraction     float64 `json:"fraction"`
+	FromAmount   string  `json:"fromAmount"`
+	ToAmount     string  `json:"toAmount,omitempty"`
+	Tool         string  `json:"tool,omitempty"`
+	Rate         float64 `json:"rate,omitempty"`
+	MarginalRate float64 `json:"marginalRate,omitempty"`
+	ImpactPct    float64 `json:"priceImpactPercent"`
+	Error        string  `json:"error,omitempty"`
+
+	fromAmount *big.Int
+	toAmount   *big.Int
+}
+
+// scaleAmount returns amount * fraction, rounded down
+func scaleAmount(amount *big.Int, fraction float64) *big.Int {
+	scaled := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(fraction))
+	result, _ := scaled.Int(nil)
+	return result
+}
+
+// humanRatio returns (num / 10^numDecimals) / (den / 10^denDecimals)
+func humanRatio(num *big.Int, numDecimals int, den *big.Int, denDecimals int) float64 {
+	if den.Sign() == 0 {
+		return 0
+	}
+	ratio := new(big.Float).Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den))
+	shift := denDecimals - numDecimals
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(shift, -shift))), nil))
+	if shift >= 0 {
+		ratio.Mul(ratio, scale)
+	} else {
+		ratio.Quo(ratio, scale)
+	}
+	f, _ := ratio.Float64()
+	return f
+}
+
+func (s *Server) analyzePriceImpactHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get all required parameters
+	fromChain := getStringArg(request, "fromChain")
+	toChain := getStringArg(request, "toChain")
+	fromToken := getStringArg(request, "fromToken")
+	toToken := getStringArg(request, "toToken")
+	fromAddress := getStringArg(request, "fromAddress")
+	fromAmount := getStringArg(request, "fromAmount")
+	slippage := getStringArg(request, "slippage")
+	threshold := defaultSplitThresholdPercent
+	if v := getStringArg(request, "splitThresholdPercent"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t <= 0 {
+			return mcp.NewToolResultError("splitThresholdPercent must be a positive number"), nil
+		}
+		threshold = t
+	}
+
+	// Validate required parameters
+	if err := ValidateChainID("fromChain", fromChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateChainID("toChain", toChain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Addresses are validated for each chain's type (EVM, SVM or UTXO)
+	fromChainType := s.chainType(ctx, fromChain, apiKey)
+	toChainType := s.chainType(ctx, toChain, apiKey)
+	if err := ValidateTokenAddressForChainType("fromToken", fromToken, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateTokenAddressForChainType("toToken", toToken, toChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddressForChainType("fromAddress", fromAddress, fromChainType); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAmount("fromAmount", fromAmount); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateSlippage(slippage); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	total, _ := new(big.Int).SetString(fromAmount, 10)
+
+	fractions := defaultImpactFractions
+	if fractionsArg := getArrayArg(request, "fractions"); fractionsArg != nil {
+		fractions = make([]float64, 0, len(fractionsArg))
+		for i, f := range fractionsArg {
+			v, err := strconv.ParseFloat(fmt.Sprintf("%v", f), 64)
+			if err != nil || v <= 0 || v > 1 {
+				return mcp.NewToolResultError(fmt.Sprintf("fractions[%d]: must be a number in (0, 1]", i)), nil
+			}
+			fractions = append(fractions, v)
+		}
+		sort.Float64s(fractions)
+	}
+	if len(fractions) == 0 || len(fractions) > maxImpactTranches {
+		return mcp.NewToolResultError(fmt.Sprintf("fractions must contain between 1 and %d values", maxImpactTranches)), nil
+	}
+
+	// Build the shared query parameters
+	base := url.Values{}
+	base.Add("fromChain", fromChain)
+	base.Add("toChain", toChain)
+	base.Add("fromToken", fromToken)
+	base.Add("toToken", toToken)
+	base.Add("fromAddress", fromAddress)
+	if slippage != "" {
+		base.Add("slippage", slippage)
+	}
+
+	tranches := make([]impactTranche, len(fractions))
+	var fromDecimals, toDecimals int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, f := range fractions {
+		amount := scaleAmount(total, f)
+		tranches[i] = impactTranche{Fraction: f, FromAmount: amount.String(), fromAmount: amount}
+		if amount.Sign() == 0 {
+			tranches[i].Error = "fraction of fromAmount rounds to zero"
+			continue
+		}
+
+		wg.Add(1)
+		go func(t *impactTranche) {
+			defer wg.Done()
+			params := url.Values{}
+			for k, v := range base {
+				params[k] = v
+			}
+			params.Set("fromAmount", t.FromAmount)
+
+			body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/quote?%s", BaseURL, params.Encode()), apiKey)
+			if err != nil {
+				t.Error = err.Error()
+				return
+			}
+			var quote quoteSummary
+			if err := json.Unmarshal(body, &quote); err != nil {
+				t.Error = fmt.Sprintf("failed to parse quote response: %v", err)
+				return
+			}
+			out, ok := new(big.Int).SetString(quote.Estimate.ToAmount, 10)
+			if !ok {
+				t.Error = "quote has no toAmount"
+				return
+			}
+			t.Tool = quote.Tool
+			t.ToAmount = out.String()
+			t.toAmount = out
+
+			mu.Lock()
+			fromDecimals, toDecimals = quote.Action.FromToken.Decimals, quote.Action.ToToken.Decimals
+			mu.Unlock()
+		}(&tranches[i])
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("request canceled: %v", ctx.Err())), nil
+	}
+
+	// Rates are in destination tokens per source token. The reference is the best rate
+	// seen across tranches: small tranches can be worse than large ones when fixed
+	// costs such as gas dominate.
+	bestRate := 0.0
+	bestIndex := -1
+	prevFrom, prevTo := new(big.Int), new(big.Int)
+	for i := range tranches {
+		t := &tranches[i]
+		if t.toAmount == nil {
+			continue
+		}
+		t.Rate = humanRatio(t.toAmount, toDecimals, t.fromAmount, fromDecimals)
+		if t.fromAmount.Cmp(prevFrom) > 0 {
+			t.MarginalRate = humanRatio(new(big.Int).Sub(t.toAmount, prevTo), toDecimals, new(big.Int).Sub(t.fromAmount, prevFrom), fromDecimals)
+		}
+		prevFrom, prevTo = t.fromAmount, t.toAmount
+		if t.Rate > bestRate {
+			bestRate, bestIndex = t.Rate, i
+		}
+	}
+	if bestIndex < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("no tranche could be quoted: %s", tranches[len(tranches)-1].Error)), nil
+	}
+	for i := range tranches {
+		if tranches[i].Rate > 0 {
+			tranches[i].ImpactPct = (1 - tranches[i].Rate/bestRate) * 100
+		}
+	}
+
+	responseData := map[string]interface{}{
+		"fromAmount":            fromAmount,
+		"tranches":              tranches,
+		"bestRateFraction":      tranches[bestIndex].Fraction,
+		"splitThresholdPercent": threshold,
+	}
+
+	// Judge the requested size by the largest tranche that could be quoted
+	full := &tranches[len(tranches)-1]
+	switch {
+	case full.toAmount == nil:
+		responseData["recommendation"] = fmt.Sprintf("The full amount could not be quoted (%s); consider splitting it into smaller transfers.", full.Error)
+		responseData["splitRecommended"] = true
+	case full.ImpactPct > threshold:
+		parts := int(1 / tranches[bestIndex].Fraction)
+		responseData["recommendation"] = fmt.Sprintf("Transferring %.0f%% at once loses %.2f%% against the best tranche rate; consider splitting into about %d transfers of %.0f%% each.",
+			full.Fraction*100, full.ImpactPct, parts, tranches[bestIndex].Fraction*100)
+		responseData["splitRecommended"] = true
+	default:
+		responseData["recommendation"] = fmt.Sprintf("Price impact at %.0f%% of the amount is %.2f%%, within the %.2f%% threshold; a single transfer is fine.",
+			full.Fraction*100, full.ImpactPct, threshold)
+		responseData["splitRecommended"] = false
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
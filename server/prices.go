@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxPriceTokens caps the number of tokens priced in a single get-token-prices call
+	maxPriceTokens = 100
+)
+
+// TokenListEntry is a token as returned by the LI.FI /v1/tokens and /v1/token endpoints
+type TokenListEntry struct {
+	Address  string `json:"address"`
+	ChainID  int    `json:"chainId"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	Name     string `json:"name"`
+	PriceUSD string `json:"priceUSD"`
+	LogoURI  string `json:"logoURI,omitempty"`
+}
+
+// tokenListResponse is the response shape of /v1/tokens, keyed by chain ID
+type tokenListResponse struct {
+	Tokens map[string][]TokenListEntry `json:"tokens"`
+}
+
+// fetchTokenList fetches the LI.FI token list for the given chain IDs
+func (s *Server) fetchTokenList(ctx context.Context, chainIDs []string, apiKey string) (map[string][]TokenListEntry, error) {
+	params := url.Values{}
+	if len(chainIDs) > 0 {
+		params.Add("chains", strings.Join(chainIDs, ","))
+	}
+
+	requestURL := fmt.Sprintf("%s/v1/tokens", BaseURL)
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	body, err := s.httpClient.Get(ctx, requestURL, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tokens: %v", err)
+	}
+
+	var tokenList tokenListResponse
+	if err := json.Unmarshal(body, &tokenList); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens response: %v", err)
+	}
+	s.tokenCache.seed(tokenList.Tokens)
+
+	return tokenList.Tokens, nil
+}
+
+// fetchToken fetches a single token from the LI.FI /v1/token endpoint
+func (s *Server) fetchToken(ctx context.Context, chain, token, apiKey string) (*TokenListEntry, error) {
+	params := url.Values{}
+	params.Add("chain", chain)
+	params.Add("token", token)
+
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/token?%s", BaseURL, params.Encode()), apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry TokenListEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %v", err)
+	}
+	if entry.Symbol != "" && entry.Address != "" {
+		s.tokenCache.put(int64(entry.ChainID), entry.Address, tokenMetadata{Symbol: entry.Symbol, Decimals: entry.Decimals, Name: entry.Name})
+	}
+
+	return &entry, nil
+}
+
+// tokenPriceRequest is one (chain, token) pair requested in get-token-prices
+type tokenPriceRequest struct {
+	chain   string
+	address string
+}
+
+// parseTokenPriceRequests parses the tokens array argument of get-token-prices
+func parseTokenPriceRequests(items []interface{}) ([]tokenPriceRequest, error) {
+	requests := make([]tokenPriceRequest, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("tokens[%d]: expected an object with 'chain' and 'address'", i)
+		}
+
+		var chain string
+		switch v := obj["chain"].(type) {
+		case string:
+			chain = strings.TrimSpace(v)
+		case float64:
+			chain = strconv.FormatInt(int64(v), 10)
+		}
+		address, _ := obj["address"].(string)
+
+		if err := ValidateChainID(fmt.Sprintf("tokens[%d].chain", i), chain); err != nil {
+			return nil, err
+		}
+		if err := ValidateTokenAddress(fmt.Sprintf("tokens[%d].address", i), address); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, tokenPriceRequest{chain: chain, address: address})
+	}
+	return requests, nil
+}
+
+func (s *Server) getTokenPricesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	items := getArrayArg(request, "tokens")
+	if len(items) == 0 {
+		return mcp.NewToolResultError("tokens array is required and must not be empty"), nil
+	}
+	if len(items) > maxPriceTokens {
+		return mcp.NewToolResultError(fmt.Sprintf("at most %d tokens can be priced per call", maxPriceTokens)), nil
+	}
+
+	requests, err := parseTokenPriceRequests(items)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Collect the distinct chains so the token list is fetched once
+	var chainIDs []string
+	seen := make(map[string]bool)
+	for _, r := range requests {
+		if !seen[r.chain] {
+			seen[r.chain] = true
+			chainIDs = append(chainIDs, r.chain)
+		}
+	}
+
+	tokensByChain, err := s.fetchTokenList(ctx, chainIDs, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Index by chain and lowercase address
+	index := make(map[string]TokenListEntry)
+	for chain, tokens := range tokensByChain {
+		for _, t := range tokens {
+			index[chain+":"+strings.ToLower(t.Address)] = t
+		}
+	}
+
+	prices := make([]map[string]interface{}, 0, len(requests))
+	var notFound []map[string]string
+	for _, r := range requests {
+		entry, ok := index[r.chain+":"+strings.ToLower(r.address)]
+		if !ok {
+			// Long-tail tokens are not always part of the list; look them up individually
+			single, err := s.fetchToken(ctx, r.chain, r.address, apiKey)
+			if err != nil {
+				notFound = append(notFound, map[string]string{"chain": r.chain, "address": r.address})
+				continue
+			}
+			entry = *single
+		}
+
+		prices = append(prices, map[string]interface{}{
+			"chainId":  r.chain,
+			"address":  checksumAddress(entry.Address),
+			"symbol":   entry.Symbol,
+			"decimals": entry.Decimals,
+			"priceUSD": entry.PriceUSD,
+		})
+	}
+
+	responseData := map[string]interface{}{
+		"prices": prices,
+	}
+	if len(notFound) > 0 {
+		responseData["notFound"] = notFound
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// tokenPrice looks up a token's decimals and LI.FI USD price. It fails when LI.FI has
+// no usable price for the token rather than valuing it at zero.
+func (s *Server) tokenPrice(ctx context.Context, chain, token, apiKey string) (*TokenListEntry, *big.Rat, error) {
+	entry, err := s.fetchToken(ctx, chain, token, apiKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up %s on chain %s: %v", token, chain, err)
+	}
+	price, ok := new(big.Rat).SetString(entry.PriceUSD)
+	if !ok || price.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("LI.FI has no USD price for %s on chain %s", entry.Symbol, chain)
+	}
+	return entry, price, nil
+}
+
+// tokenToUSD values an amount in base units at priceUSD per whole token
+func tokenToUSD(amount *big.Int, decimals int, price *big.Rat) *big.Rat {
+	value := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	return value.Mul(value, price)
+}
+
+// usdToToken converts a USD value into base units of a token priced at priceUSD per
+// whole token, rounding down
+func usdToToken(usd *big.Rat, decimals int, price *big.Rat) *big.Int {
+	value := new(big.Rat).Quo(usd, price)
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	return new(big.Int).Quo(value.Num(), value.Denom())
+}
+
+func (s *Server) convertToUSDHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	token := getStringArg(request, "token")
+	amount := getStringArg(request, "amount")
+	usdAmount := getStringArg(request, "usdAmount")
+
+	if err := ValidateChainID("chain", chain); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if token == "" {
+		token = ZeroAddress
+	}
+	if (amount == "") == (usdAmount == "") {
+		return mcp.NewToolResultError("provide exactly one of amount (to convert to USD) or usdAmount (to convert from USD)"), nil
+	}
+
+	entry, price, err := s.tokenPrice(ctx, chain, token, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	responseData := map[string]interface{}{
+		"chainId": chain,
+		"token": map[string]interface{}{
+			"address":  checksumAddress(entry.Address),
+			"symbol":   entry.Symbol,
+			"decimals": entry.Decimals,
+		},
+		"priceUSD": entry.PriceUSD,
+	}
+
+	if amount != "" {
+		baseUnits, err := parseUnits(amount, entry.Decimals)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("amount: %v", err)), nil
+		}
+		responseData["amount"] = formatUnits(baseUnits, entry.Decimals)
+		responseData["amountBaseUnits"] = baseUnits.String()
+		responseData["usdValue"] = tokenToUSD(baseUnits, entry.Decimals, price).FloatString(2)
+	} else {
+		usd, ok := new(big.Rat).SetString(strings.TrimSpace(usdAmount))
+		if !ok || usd.Sign() < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("usdAmount must be a non-negative decimal number, got %q", usdAmount)), nil
+		}
+		baseUnits := usdToToken(usd, entry.Decimals, price)
+		responseData["usdAmount"] = usd.FloatString(2)
+		responseData["amount"] = formatUnits(baseUnits, entry.Decimals)
+		responseData["amountBaseUnits"] = baseUnits.String()
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MCP prompts template the common multi-step workflows so agents call the right
+// tools in the right order. Signing and broadcasting always happen in the user's
+// own wallet; the prompts say so explicitly.
+
+// registerPrompts registers all prompts with the MCP server
+func (s *Server) registerPrompts() {
+	s.mcpServer.AddPrompt(mcp.NewPrompt("bridge-tokens",
+		mcp.WithPromptDescription("Step-by-step plan for bridging tokens between two chains with LI.FI: quote, allowance check, signing in the user's wallet, and tracking until the transfer completes."),
+		mcp.WithArgument("fromChain", mcp.ArgumentDescription("Source chain ID or name (e.g., '1' or 'ethereum')."), mcp.RequiredArgument()),
+		mcp.WithArgument("toChain", mcp.ArgumentDescription("Destination chain ID or name (e.g., '42161' or 'arbitrum')."), mcp.RequiredArgument()),
+		mcp.WithArgument("token", mcp.ArgumentDescription("Token symbol or address to bridge (e.g., 'USDC')."), mcp.RequiredArgument()),
+		mcp.WithArgument("amount", mcp.ArgumentDescription("Human-readable amount to bridge (e.g., '100')."), mcp.RequiredArgument()),
+		mcp.WithArgument("fromAddress", mcp.ArgumentDescription("Sender wallet address."), mcp.RequiredArgument()),
+		mcp.WithArgument("toAddress", mcp.ArgumentDescription("Recipient wallet address on the destination chain. Defaults to fromAddress.")),
+	), s.bridgeTokensPrompt)
+
+	s.mcpServer.AddPrompt(mcp.NewPrompt("swap-on-chain",
+		mcp.WithPromptDescription("Step-by-step plan for swapping one token for another on a single chain with LI.FI: quote, allowance check, signing in the user's wallet, and confirming the receipt."),
+		mcp.WithArgument("chain", mcp.ArgumentDescription("Chain ID or name (e.g., '8453' or 'base')."), mcp.RequiredArgument()),
+		mcp.WithArgument("fromToken", mcp.ArgumentDescription("Token symbol or address to sell (e.g., 'ETH')."), mcp.RequiredArgument()),
+		mcp.WithArgument("toToken", mcp.ArgumentDescription("Token symbol or address to buy (e.g., 'USDC')."), mcp.RequiredArgument()),
+		mcp.WithArgument("amount", mcp.ArgumentDescription("Human-readable amount of fromToken to sell (e.g., '0.5')."), mcp.RequiredArgument()),
+		mcp.WithArgument("fromAddress", mcp.ArgumentDescription("Wallet address performing the swap."), mcp.RequiredArgument()),
+	), s.swapOnChainPrompt)
+
+	s.mcpServer.AddPrompt(mcp.NewPrompt("check-transfer-status",
+		mcp.WithPromptDescription("Check on a cross-chain transfer and explain its state, including what to do if it is stuck or failed."),
+		mcp.WithArgument("txHash", mcp.ArgumentDescription("Source chain transaction hash."), mcp.RequiredArgument()),
+		mcp.WithArgument("fromChain", mcp.ArgumentDescription("Source chain ID, if known.")),
+		mcp.WithArgument("toChain", mcp.ArgumentDescription("Destination chain ID, if known.")),
+	), s.checkTransferStatusPrompt)
+}
+
+// promptArg returns a trimmed prompt argument, or fallback if it is empty
+func promptArg(request mcp.GetPromptRequest, name, fallback string) string {
+	if v := strings.TrimSpace(request.Params.Arguments[name]); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// requirePromptArgs returns an error naming the first missing required argument
+func requirePromptArgs(request mcp.GetPromptRequest, names ...string) error {
+	for _, name := range names {
+		if promptArg(request, name, "") == "" {
+			return fmt.Errorf("missing required argument: %s", name)
+		}
+	}
+	return nil
+}
+
+func (s *Server) bridgeTokensPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	if err := requirePromptArgs(request, "fromChain", "toChain", "token", "amount", "fromAddress"); err != nil {
+		return nil, err
+	}
+
+	fromChain := promptArg(request, "fromChain", "")
+	toChain := promptArg(request, "toChain", "")
+	token := promptArg(request, "token", "")
+	amount := promptArg(request, "amount", "")
+	fromAddress := promptArg(request, "fromAddress", "")
+	toAddress := promptArg(request, "toAddress", fromAddress)
+
+	text := fmt.Sprintf(`Bridge %s %s from chain %s to chain %s for wallet %s (recipient %s).
+
+Follow these steps using the LI.FI tools:
+1. Resolve both chains with get-chain-by-name or get-chain-by-id to get numeric chain IDs.
+2. Look up %s on both chains with get-token to get the token addresses and decimals.
+3. Convert %s to base units using the source token's decimals (e.g. 1.5 USDC with 6 decimals is 1500000).
+4. Check the sender's balance with get-token-balance (or get-native-token-balance for the native token).
+5. Call get-quote with fromChain, toChain, fromToken, toToken, fromAddress=%s, toAddress=%s and fromAmount in base units.
+6. If fromToken is not the native token, call get-allowance with spenderAddress set to the quote's estimate.approvalAddress.
+   If the allowance is below fromAmount, the user must approve that spender in their own wallet first.
+7. Show the user the quote (expected output, toAmountMin, fees, estimated duration) and ask them to sign and send the quote's transactionRequest in their own wallet. This server cannot sign transactions.
+8. Once the user provides the transaction hash, call wait-for-receipt on the source chain to confirm it was mined successfully.
+9. Call track-transfer with the hash, bridge (the quote's tool), fromChain and toChain, and report the final status.`,
+		amount, token, fromChain, toChain, fromAddress, toAddress,
+		token, amount, fromAddress, toAddress)
+
+	return mcp.NewGetPromptResult(
+		"Bridge tokens with LI.FI",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
+
+func (s *Server) swapOnChainPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	if err := requirePromptArgs(request, "chain", "fromToken", "toToken", "amount", "fromAddress"); err != nil {
+		return nil, err
+	}
+
+	chain := promptArg(request, "chain", "")
+	fromToken := promptArg(request, "fromToken", "")
+	toToken := promptArg(request, "toToken", "")
+	amount := promptArg(request, "amount", "")
+	fromAddress := promptArg(request, "fromAddress", "")
+
+	text := fmt.Sprintf(`Swap %s %s for %s on chain %s for wallet %s.
+
+Follow these steps using the LI.FI tools:
+1. Resolve the chain with get-chain-by-name or get-chain-by-id to get its numeric chain ID.
+2. Look up %s and %s with get-token to get their addresses and decimals.
+3. Convert %s to base units using %s's decimals.
+4. Check the wallet's balance with get-token-balance (or get-native-token-balance for the native token).
+5. Call get-quote with fromChain and toChain both set to the chain ID, the two token addresses, fromAddress=%s and fromAmount in base units.
+6. If %s is not the native token, call get-allowance with spenderAddress set to the quote's estimate.approvalAddress.
+   If the allowance is below fromAmount, the user must approve that spender in their own wallet first.
+7. Show the user the expected output, toAmountMin and fees, and ask them to sign and send the quote's transactionRequest in their own wallet. This server cannot sign transactions.
+8. Once the user provides the transaction hash, call wait-for-receipt and report whether the swap succeeded.`,
+		amount, fromToken, toToken, chain, fromAddress,
+		fromToken, toToken, amount, fromToken, fromAddress, fromToken)
+
+	return mcp.NewGetPromptResult(
+		"Swap tokens on one chain with LI.FI",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
+
+func (s *Server) checkTransferStatusPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	if err := requirePromptArgs(request, "txHash"); err != nil {
+		return nil, err
+	}
+
+	txHash := promptArg(request, "txHash", "")
+	fromChain := promptArg(request, "fromChain", "unknown")
+	toChain := promptArg(request, "toChain", "unknown")
+
+	text := fmt.Sprintf(`Check the status of the LI.FI transfer with source transaction %s (fromChain: %s, toChain: %s).
+
+1. Call get-status with the txHash (and fromChain/toChain if known).
+2. Explain the status to the user:
+   - DONE: report the receiving transaction hash and amount. A substatus of PARTIAL or REFUNDED means the user received a different token than requested; say which.
+   - PENDING: explain which step the transfer is waiting on, and offer to call track-transfer to wait for completion.
+   - FAILED or INVALID: report the substatus message and suggest contacting LI.FI support with the transaction hash.
+   - NOT_FOUND: the transaction may not be indexed yet; suggest retrying in a minute and double-checking the source chain.`,
+		txHash, fromChain, toChain)
+
+	return mcp.NewGetPromptResult(
+		"Check a LI.FI transfer",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
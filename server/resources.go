@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MCP resources expose LI.FI reference data (chains, tokens, bridges/exchanges) so
+// clients can load it once without spending tool calls.
+
+// registerResources registers all resources and resource templates with the MCP server
+func (s *Server) registerResources() {
+	s.mcpServer.AddResource(mcp.NewResource("lifi://chains", "LI.FI chains",
+		mcp.WithResourceDescription("All chains supported by LI.FI with IDs, keys, native tokens, RPC URLs and block explorers."),
+		mcp.WithMIMEType("application/json"),
+	), s.readChainsResource)
+
+	s.mcpServer.AddResource(mcp.NewResource("lifi://tools", "LI.FI bridges and exchanges",
+		mcp.WithResourceDescription("Keys and names of all bridges and DEX aggregators LI.FI can route through. Keys are used in allowBridges/allowExchanges filters."),
+		mcp.WithMIMEType("application/json"),
+	), s.readToolsResource)
+
+	s.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("lifi://tokens/{chainId}", "LI.FI tokens by chain",
+		mcp.WithTemplateDescription("Tokens supported by LI.FI on one chain, with addresses, symbols, decimals and USD prices."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.readTokensResource)
+}
+
+func (s *Server) readChainsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Ensure the chains are loaded
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	s.chains.mu.RLock()
+	jsonData, err := json.Marshal(s.chains.data)
+	s.chains.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("error serializing chain data: %v", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
+
+func (s *Server) readToolsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/tools", BaseURL), apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+
+	tools, err := summarizeTools(body)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize tools: %v", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
+
+func (s *Server) readTokensResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Template variables arrive as []string from the URI template matcher
+	var chainID string
+	switch v := request.Params.Arguments["chainId"].(type) {
+	case []string:
+		if len(v) > 0 {
+			chainID = v[0]
+		}
+	case string:
+		chainID = v
+	}
+	if chainID, _ = url.PathUnescape(chainID); chainID == "" {
+		return nil, fmt.Errorf("chainId is required in the resource URI")
+	}
+	id, err := s.resolveChainID(ctx, chainID, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	chainID = strconv.Itoa(id)
+
+	tokensByChain, err := s.fetchTokenList(ctx, []string{chainID}, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := tokensByChain[chainID]
+	if tokens == nil {
+		tokens = []TokenListEntry{}
+	}
+
+	jsonData, err := json.Marshal(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize tokens: %v", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}, nil
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RevertErrorsABI contains the custom errors of the LI.FI Diamond (GenericErrors.sol and
+// facet-specific errors) plus the OpenZeppelin v5 ERC20 errors tokens commonly revert with
+const RevertErrorsABI = `[
+	{"type":"error","name":"AlreadyInitialized","inputs":[]},
+	{"type":"error","name":"CannotAuthoriseSelf","inputs":[]},
+	{"type":"error","name":"CannotBridgeToSameNetwork","inputs":[]},
+	{"type":"error","name":"ContractCallNotAllowed","inputs":[]},
+	{"type":"error","name":"CumulativeSlippageTooHigh","inputs":[{"name":"minAmount","type":"uint256"},{"name":"receivedAmount","type":"uint256"}]},
+	{"type":"error","name":"ExternalCallFailed","inputs":[]},
+	{"type":"error","name":"FunctionDoesNotExist","inputs":[]},
+	{"type":"error","name":"InformationMismatch","inputs":[]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"required","type":"uint256"},{"name":"balance","type":"uint256"}]},
+	{"type":"error","name":"InvalidAmount","inputs":[]},
+	{"type":"error","name":"InvalidCallData","inputs":[]},
+	{"type":"error","name":"InvalidConfig","inputs":[]},
+	{"type":"error","name":"InvalidContract","inputs":[]},
+	{"type":"error","name":"InvalidDestinationChain","inputs":[]},
+	{"type":"error","name":"InvalidFallbackAddress","inputs":[]},
+	{"type":"error","name":"InvalidReceiver","inputs":[]},
+	{"type":"error","name":"InvalidSendingToken","inputs":[]},
+	{"type":"error","name":"NativeAssetNotSupported","inputs":[]},
+	{"type":"error","name":"NativeAssetTransferFailed","inputs":[]},
+	{"type":"error","name":"NoSwapDataProvided","inputs":[]},
+	{"type":"error","name":"NoSwapFromZeroBalance","inputs":[]},
+	{"type":"error","name":"NoTransferToNullAddress","inputs":[]},
+	{"type":"error","name":"NotAContract","inputs":[]},
+	{"type":"error","name":"NotInitialized","inputs":[]},
+	{"type":"error","name":"NullAddrIsNotAValidSpender","inputs":[]},
+	{"type":"error","name":"NullAddrIsNotAnERC20Token","inputs":[]},
+	{"type":"error","name":"OnlyContractOwner","inputs":[]},
+	{"type":"error","name":"RecoveryAddressCannotBeZero","inputs":[]},
+	{"type":"error","name":"ReentrancyError","inputs":[]},
+	{"type":"error","name":"TokenNotSupported","inputs":[]},
+	{"type":"error","name":"UnAuthorized","inputs":[]},
+	{"type":"error","name":"UnsupportedChainId","inputs":[{"name":"chainId","type":"uint256"}]},
+	{"type":"error","name":"WithdrawFailed","inputs":[]},
+	{"type":"error","name":"ZeroAmount","inputs":[]},
+	{"type":"error","name":"ERC20InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"ERC20InsufficientAllowance","inputs":[{"name":"spender","type":"address"},{"name":"allowance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"ERC20InvalidReceiver","inputs":[{"name":"receiver","type":"address"}]},
+	{"type":"error","name":"ERC20InvalidSpender","inputs":[{"name":"spender","type":"address"}]}
+]`
+
+// Selectors of the built-in Solidity revert types
+var (
+	errorStringSelector = [4]byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector       = [4]byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons describes the Solidity Panic(uint256) codes
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// knownErrors indexes RevertErrorsABI by selector
+var knownErrors = func() map[[4]byte]abi.Error {
+	parsed, err := abi.JSON(strings.NewReader(RevertErrorsABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid RevertErrorsABI: %v", err))
+	}
+	bySelector := make(map[[4]byte]abi.Error, len(parsed.Errors))
+	for _, e := range parsed.Errors {
+		var selector [4]byte
+		copy(selector[:], e.ID[:4])
+		bySelector[selector] = e
+	}
+	return bySelector
+}()
+
+// RevertError is a decoded revert payload
+type RevertError struct {
+	Selector string                 `json:"selector"`
+	Name     string                 `json:"name"`
+	Args     map[string]interface{} `json:"args"`
+	Reason   string                 `json:"reason"`
+}
+
+// decodeRevert decodes revert data as Error(string), Panic(uint256) or a known custom
+// error. Unknown selectors are returned with an empty name and the raw data as args.
+func decodeRevert(data []byte) *RevertError {
+	if len(data) < 4 {
+		return nil
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	decoded := &RevertError{
+		Selector: hexutil.Encode(selector[:]),
+		Args:     map[string]interface{}{},
+	}
+
+	switch selector {
+	case errorStringSelector:
+		if message, err := abi.UnpackRevert(data); err == nil {
+			decoded.Name = "Error"
+			decoded.Args["message"] = message
+			decoded.Reason = message
+			return decoded
+		}
+	case panicSelector:
+		if len(data) == 36 {
+			code := new(big.Int).SetBytes(data[4:])
+			description, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				description = "unknown panic code"
+			}
+			decoded.Name = "Panic"
+			decoded.Args["code"] = fmt.Sprintf("0x%x", code)
+			decoded.Reason = fmt.Sprintf("panic: %s (0x%x)", description, code)
+			return decoded
+		}
+	}
+
+	if customErr, ok := knownErrors[selector]; ok {
+		values, err := customErr.Inputs.Unpack(data[4:])
+		if err == nil {
+			decoded.Name = customErr.Name
+			parts := make([]string, 0, len(values))
+			for i, v := range values {
+				name := customErr.Inputs[i].Name
+				if name == "" {
+					name = fmt.Sprintf("arg%d", i)
+				}
+				decoded.Args[name] = formatABIValue(v)
+				parts = append(parts, fmt.Sprintf("%s=%v", name, decoded.Args[name]))
+			}
+			decoded.Reason = customErr.Name
+			if len(parts) > 0 {
+				decoded.Reason = fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(parts, ", "))
+			}
+			return decoded
+		}
+	}
+
+	decoded.Args["data"] = hexutil.Encode(data[4:])
+	decoded.Reason = fmt.Sprintf("unknown custom error %s", decoded.Selector)
+	return decoded
+}
+
+// formatABIValue renders an unpacked ABI value as a JSON-friendly string where the
+// default encoding would lose precision or be unreadable
+func formatABIValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *big.Int:
+		return val.String()
+	case []byte:
+		return hexutil.Encode(val)
+	case [32]byte:
+		return hexutil.Encode(val[:])
+	case fmt.Stringer:
+		return val.String()
+	}
+	return v
+}
+
+// revertDataFromError extracts the raw revert data from an eth_call error, if the node returned any
+func revertDataFromError(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return nil
+	}
+	return data
+}
+
+// isRevert reports whether an eth_call error is the call reverting, as calls to a
+// function the contract does not implement do, rather than the node failing
+func isRevert(err error) bool {
+	return revertDataFromError(err) != nil || strings.Contains(err.Error(), "execution reverted")
+}
+
+// revertFromError decodes the revert carried by an eth_call error. Nodes that do not
+// return revert data are handled by parsing the reason out of the error message.
+func revertFromError(err error) *RevertError {
+	if decoded := decodeRevert(revertDataFromError(err)); decoded != nil {
+		return decoded
+	}
+	if parts := strings.SplitN(err.Error(), "execution reverted:", 2); len(parts) > 1 {
+		message := strings.TrimSpace(parts[1])
+		return &RevertError{
+			Name:   "Error",
+			Args:   map[string]interface{}{"message": message},
+			Reason: message,
+		}
+	}
+	return nil
+}
+
+// revertErrorResult builds an error result for a failed contract call. When the revert
+// can be decoded, the result is JSON with the structured revert alongside the message.
+func revertErrorResult(message string, err error) *mcp.CallToolResult {
+	revert := revertFromError(err)
+	if revert == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s: %v", message, err))
+	}
+
+	jsonResponse, marshalErr := json.Marshal(map[string]interface{}{
+		"error":  fmt.Sprintf("%s: %v", message, err),
+		"revert": revert,
+	})
+	if marshalErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s: %v. Revert reason: %s", message, err, revert.Reason))
+	}
+	return mcp.NewToolResultError(string(jsonResponse))
+}
@@ -0,0 +1,327 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// rpcFailureCooldown is how long a failed endpoint is tried only after healthy ones
+	rpcFailureCooldown = time.Minute
+
+	// rpcProbeInterval is how often the candidate endpoints of a chain are re-probed
+	rpcProbeInterval = 5 * time.Minute
+
+	// rpcMaxBlockAge marks an endpoint unhealthy if its latest block is older than this
+	rpcMaxBlockAge = 10 * time.Minute
+
+	// maxRPCResponseSize bounds how much of a JSON-RPC response is buffered to check it
+	// for errors. Larger responses (big log ranges, traces, full blocks) are passed on
+	// unchecked, since an error about the endpoint is never that large.
+	maxRPCResponseSize = 32 << 20
+)
+
+// failoverRPCErrors are the JSON-RPC error codes that describe the endpoint rather than
+// the request: rate limits, unavailable resources, internal errors and methods the
+// endpoint does not serve. Another endpoint may well answer the same request.
+var failoverRPCErrors = map[int]bool{
+	-32601: true, // method not found
+	-32603: true, // internal error
+	-32001: true, // resource not found
+	-32002: true, // resource unavailable
+	-32005: true, // limit exceeded
+}
+
+// endpointHealth is what is known about one RPC endpoint
+type endpointHealth struct {
+	latency     time.Duration
+	failures    int
+	lastFailure time.Time
+	lastProbe   time.Time
+
+	// wrongChain is set when the endpoint serves another chain. It is never cleared:
+	// such an endpoint must not serve reads, however well it responds.
+	wrongChain bool
+}
+
+// rpcHealth scores RPC endpoints from probes and real traffic
+type rpcHealth struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointHealth
+}
+
+func newRPCHealth() *rpcHealth {
+	return &rpcHealth{endpoints: make(map[string]*endpointHealth)}
+}
+
+// endpoint returns the record for url. Callers must hold h.mu.
+func (h *rpcHealth) endpoint(url string) *endpointHealth {
+	e, ok := h.endpoints[url]
+	if !ok {
+		e = &endpointHealth{}
+		h.endpoints[url] = e
+	}
+	return e
+}
+
+func (h *rpcHealth) recordSuccess(url string, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e := h.endpoint(url)
+	e.latency = latency
+	e.failures = 0
+}
+
+func (h *rpcHealth) recordFailure(url string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e := h.endpoint(url)
+	e.failures++
+	e.lastFailure = time.Now()
+}
+
+// recordWrongChain excludes an endpoint that serves another chain from failover for good
+func (h *rpcHealth) recordWrongChain(url string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e := h.endpoint(url)
+	e.wrongChain = true
+	e.failures++
+	e.lastFailure = time.Now()
+}
+
+// order sorts urls best first: endpoints without a recent failure before those in
+// cooldown, then by fewest failures and lowest latency. Unmeasured endpoints keep the
+// order of the chain's public RPC list among equals. Endpoints found serving another
+// chain are left out.
+func (h *rpcHealth) order(urls []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	type scored struct {
+		url      string
+		cooling  bool
+		failures int
+		latency  time.Duration
+	}
+	now := time.Now()
+	list := make([]scored, 0, len(urls))
+	for _, u := range urls {
+		e := h.endpoint(u)
+		if e.wrongChain {
+			continue
+		}
+		list = append(list, scored{
+			url:      u,
+			cooling:  e.failures > 0 && now.Sub(e.lastFailure) < rpcFailureCooldown,
+			failures: e.failures,
+			latency:  e.latency,
+		})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.cooling != b.cooling {
+			return !a.cooling
+		}
+		if a.failures != b.failures {
+			return a.failures < b.failures
+		}
+		if a.latency == 0 || b.latency == 0 {
+			return false
+		}
+		return a.latency < b.latency
+	})
+
+	ordered := make([]string, len(list))
+	for i, s := range list {
+		ordered[i] = s.url
+	}
+	return ordered
+}
+
+// needsProbe reports whether any of urls is due for a health probe and marks them as probed
+func (h *rpcHealth) needsProbe(urls []string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	due := false
+	for _, u := range urls {
+		e := h.endpoint(u)
+		if time.Since(e.lastProbe) >= rpcProbeInterval {
+			e.lastProbe = time.Now()
+			due = true
+		}
+	}
+	return due
+}
+
+// probe checks that an endpoint serves the expected chain and that its head is recent
+func (h *rpcHealth) probe(ctx context.Context, rpcUrl string, chainID int64) error {
+	probeCtx, cancel := context.WithTimeout(ctx, rpcHealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	client, err := ethclient.DialContext(probeCtx, rpcUrl)
+	if err != nil {
+		h.recordFailure(rpcUrl)
+		return err
+	}
+	defer client.Close()
+
+	id, err := client.ChainID(probeCtx)
+	if err == nil && id.Int64() != chainID {
+		h.recordWrongChain(rpcUrl)
+		return fmt.Errorf("serves chain %s, expected %d", id, chainID)
+	}
+	if err == nil {
+		var header *types.Header
+		header, err = client.HeaderByNumber(probeCtx, nil)
+		if err == nil {
+			if age := time.Since(time.Unix(int64(header.Time), 0)); age > rpcMaxBlockAge {
+				err = fmt.Errorf("latest block is %s old", age.Round(time.Second))
+			}
+		}
+	}
+	if err != nil {
+		h.recordFailure(rpcUrl)
+		return err
+	}
+
+	h.recordSuccess(rpcUrl, time.Since(start))
+	return nil
+}
+
+// endpointRPCError returns the first JSON-RPC error in a response body (single or batch)
+// whose code is one of failoverRPCErrors, or nil if the endpoint answered properly.
+// Errors about the request itself, such as reverts and invalid params, are left for
+// the caller.
+func endpointRPCError(body []byte) error {
+	type rpcError struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	var responses []rpcError
+	if err := json.Unmarshal(body, &responses); err != nil {
+		var single rpcError
+		if err := json.Unmarshal(body, &single); err != nil {
+			return nil
+		}
+		responses = []rpcError{single}
+	}
+	for _, r := range responses {
+		if r.Error != nil && failoverRPCErrors[r.Error.Code] {
+			return fmt.Errorf("JSON-RPC error %d: %s", r.Error.Code, r.Error.Message)
+		}
+	}
+	return nil
+}
+
+// failoverTransport sends each JSON-RPC request to the healthiest candidate endpoint
+// and transparently retries it on the next one if the endpoint fails, whether with a
+// transport error, an HTTP error status or a JSON-RPC error about the endpoint. All
+// candidates serve the same chain, so any of them can answer any request.
+type failoverTransport struct {
+	urls   []string
+	health *rpcHealth
+	base   http.RoundTripper
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	candidates := t.health.order(t.urls)
+	for i, candidate := range candidates {
+		target, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+
+		attempt := req.Clone(req.Context())
+		attempt.URL = target
+		attempt.Host = target.Host
+		attempt.Body = io.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+
+		start := time.Now()
+		resp, err := t.base.RoundTrip(attempt)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			// Buffer the response to look for JSON-RPC errors about the endpoint
+			var respBody []byte
+			respBody, err = io.ReadAll(io.LimitReader(resp.Body, maxRPCResponseSize+1))
+			if err == nil && len(respBody) > maxRPCResponseSize {
+				// Stream the rest of a large response rather than cutting it off
+				resp.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+				t.health.recordSuccess(candidate, time.Since(start))
+				return resp, nil
+			}
+			resp.Body.Close()
+			if err == nil {
+				resp.Body = io.NopCloser(bytes.NewReader(respBody))
+				resp.ContentLength = int64(len(respBody))
+				rpcErr := endpointRPCError(respBody)
+				if rpcErr == nil {
+					t.health.recordSuccess(candidate, time.Since(start))
+					return resp, nil
+				}
+				// The last endpoint's answer goes back to the caller as is
+				if i == len(candidates)-1 {
+					t.health.recordFailure(candidate)
+					return resp, nil
+				}
+				err = fmt.Errorf("%s returned %v", target.Host, rpcErr)
+			}
+		} else if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("%s returned HTTP %d", target.Host, resp.StatusCode)
+		}
+
+		// Don't try further endpoints, or blame this one, once the caller has given up
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+		t.health.recordFailure(candidate)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no usable RPC endpoints")
+	}
+	return nil, lastErr
+}
+
+// dialFailover creates a client whose HTTP requests fail over across urls
+func dialFailover(ctx context.Context, urls []string, health *rpcHealth) (*ethclient.Client, error) {
+	httpClient := &http.Client{
+		Transport: &failoverTransport{urls: urls, health: health, base: http.DefaultTransport},
+	}
+	client, err := rpc.DialOptions(ctx, urls[0], rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postRPC(t *testing.T, transport *failoverTransport) []byte {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, transport.urls[0], strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return body
+}
+
+func TestFailoverTransportLargeResponse(t *testing.T) {
+	large := append([]byte(`{"jsonrpc":"2.0","id":1,"result":"`), bytes.Repeat([]byte("a"), maxRPCResponseSize)...)
+	large = append(large, `"}`...)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(large)
+	}))
+	defer srv.Close()
+
+	transport := &failoverTransport{urls: []string{srv.URL}, health: newRPCHealth(), base: http.DefaultTransport}
+	if body := postRPC(t, transport); !bytes.Equal(body, large) {
+		t.Fatalf("got %d bytes, want the full %d byte response", len(body), len(large))
+	}
+}
+
+func TestFailoverTransportEndpointError(t *testing.T) {
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+	}))
+	defer limited.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":[]}`))
+	}))
+	defer healthy.Close()
+
+	transport := &failoverTransport{urls: []string{limited.URL, healthy.URL}, health: newRPCHealth(), base: http.DefaultTransport}
+	if body := postRPC(t, transport); !strings.Contains(string(body), `"result":[]`) {
+		t.Fatalf("got %s, want the healthy endpoint's answer", body)
+	}
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// rpcIdleTimeout is how long a client stays in the pool after its last caller released
+	// it. Clients that are checked out are never evicted.
+	rpcIdleTimeout = 5 * time.Minute
+
+	// rpcHealthCheckInterval is how long a client is trusted before it is probed again on reuse
+	rpcHealthCheckInterval = 30 * time.Second
+
+	// rpcHealthCheckTimeout bounds the probe so a dead endpoint doesn't stall the caller
+	rpcHealthCheckTimeout = 5 * time.Second
+
+	// rpcEvictionInterval is how often the pool looks for idle clients
+	rpcEvictionInterval = time.Minute
+)
+
+// rpcPool keeps one ethclient per RPC URL and reuses it across tool calls, so agents
+// doing many sequential reads don't pay for a new connection on every call.
+type rpcPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+	logger  *slog.Logger
+	stop    chan struct{}
+	once    sync.Once
+
+	// ctx is canceled on close, aborting background health probes
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// candidates maps a primary RPC URL to the endpoints it can fail over to
+	candidates map[string][]string
+	health     *rpcHealth
+}
+
+// pooledClient is a pooled ethclient. All fields but client are guarded by rpcPool.mu.
+type pooledClient struct {
+	client      *ethclient.Client
+	lastUsed    time.Time
+	lastHealthy time.Time
+
+	// inUse counts the callers that have the client checked out
+	inUse int
+	// retired is set when the client is dropped from the pool while checked out; the
+	// last release closes it
+	retired bool
+}
+
+func newRPCPool(logger *slog.Logger) *rpcPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &rpcPool{
+		ctx:        ctx,
+		cancel:     cancel,
+		clients:    make(map[string]*pooledClient),
+		logger:     logger,
+		stop:       make(chan struct{}),
+		candidates: make(map[string][]string),
+		health:     newRPCHealth(),
+	}
+	go p.evictLoop()
+	return p
+}
+
+// get checks out a pooled client for rpcUrl, dialing a new one if none exists or the
+// existing one fails its health check. The client stays open until release is called,
+// which callers must do once they are done with it (typically with defer). Callers must
+// not Close the returned client.
+func (p *rpcPool) get(ctx context.Context, rpcUrl string) (*ethclient.Client, func(), error) {
+	p.mu.Lock()
+	pc, ok := p.clients[rpcUrl]
+	var healthy bool
+	if ok {
+		pc.inUse++
+		healthy = time.Since(pc.lastHealthy) < rpcHealthCheckInterval
+	}
+	p.mu.Unlock()
+
+	if ok {
+		if healthy || p.probe(ctx, pc.client) {
+			if !healthy {
+				p.mu.Lock()
+				pc.lastHealthy = time.Now()
+				p.mu.Unlock()
+			}
+			return pc.client, p.releaser(pc), nil
+		}
+		p.release(pc)
+
+		// A probe cut short by the caller says nothing about the endpoint
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		p.logger.Debug("Pooled RPC client failed health check, redialing", "rpcUrl", rpcUrl)
+		p.remove(rpcUrl, pc)
+	}
+
+	p.mu.Lock()
+	candidates := p.candidates[rpcUrl]
+	p.mu.Unlock()
+
+	var client *ethclient.Client
+	var err error
+	if len(candidates) > 1 {
+		client, err = dialFailover(ctx, candidates, p.health)
+	} else {
+		client, err = ethclient.DialContext(ctx, rpcUrl)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another caller may have dialed the same URL concurrently; keep the first one
+	if existing, ok := p.clients[rpcUrl]; ok {
+		client.Close()
+		existing.inUse++
+		return existing.client, p.releaser(existing), nil
+	}
+
+	pc = &pooledClient{client: client, lastUsed: now, lastHealthy: now, inUse: 1}
+	p.clients[rpcUrl] = pc
+	return client, p.releaser(pc), nil
+}
+
+// releaser returns the release function for a checked-out client; calling it more
+// than once has no further effect
+func (p *rpcPool) releaser(pc *pooledClient) func() {
+	var once sync.Once
+	return func() { once.Do(func() { p.release(pc) }) }
+}
+
+// release checks a client back in, closing it if it was retired while checked out
+func (p *rpcPool) release(pc *pooledClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc.inUse--
+	pc.lastUsed = time.Now()
+	if pc.retired && pc.inUse == 0 {
+		pc.client.Close()
+	}
+}
+
+// setCandidates registers the endpoints of chainID that a client for primary may fail
+// over to, and probes them in the background when they are due
+func (p *rpcPool) setCandidates(primary string, urls []string, chainID int64) {
+	p.mu.Lock()
+	p.candidates[primary] = urls
+	p.mu.Unlock()
+
+	if len(urls) < 2 || !p.health.needsProbe(urls) {
+		return
+	}
+	go func() {
+		for _, u := range urls {
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+			if err := p.health.probe(p.ctx, u, chainID); err != nil {
+				p.logger.Debug("RPC endpoint failed health probe", "rpcUrl", u, "error", err)
+			}
+		}
+	}()
+}
+
+// probe checks that the endpoint still answers eth_blockNumber
+func (p *rpcPool) probe(ctx context.Context, client *ethclient.Client) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, rpcHealthCheckTimeout)
+	defer cancel()
+	_, err := client.BlockNumber(probeCtx)
+	return err == nil
+}
+
+// remove drops pc if it is still the pooled client for rpcUrl, closing it once no
+// caller has it checked out
+func (p *rpcPool) remove(rpcUrl string, pc *pooledClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if current, ok := p.clients[rpcUrl]; ok && current == pc {
+		delete(p.clients, rpcUrl)
+		if pc.inUse == 0 {
+			pc.client.Close()
+		} else {
+			pc.retired = true
+		}
+	}
+}
+
+// evictLoop periodically closes clients that nobody has checked out and that have been
+// idle for longer than rpcIdleTimeout
+func (p *rpcPool) evictLoop() {
+	ticker := time.NewTicker(rpcEvictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			for url, pc := range p.clients {
+				if pc.inUse == 0 && time.Since(pc.lastUsed) > rpcIdleTimeout {
+					pc.client.Close()
+					delete(p.clients, url)
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// close stops the eviction loop and closes all pooled clients
+func (p *rpcPool) close() {
+	p.once.Do(func() {
+		close(p.stop)
+		p.cancel()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for url, pc := range p.clients {
+			pc.client.Close()
+			delete(p.clients, url)
+		}
+	})
+}
@@ -4,45 +4,270 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
 	BaseURL = "https://li.quest"
+
+	// DefaultChainsCacheTTL is how long chain data is served before it is refreshed
+	DefaultChainsCacheTTL = time.Hour
 )
 
 // Server represents the LiFi MCP server (multi-tenant, stateless)
 type Server struct {
-	mcpServer  *mcpserver.MCPServer
-	httpClient *HTTPClient
-	version    string
-	logger     *slog.Logger
+	mcpServer    *mcpserver.MCPServer
+	httpClient   *HTTPClient
+	externalHTTP *http.Client
+	apiHTTP      *http.Client
+	rpcClients   *rpcPool
+	version      string
+	logger       *slog.Logger
+
+	// tools and middlewares are kept for in-process calls, which bypass the MCP server
+	tools       []mcpserver.ServerTool
+	middlewares []mcpserver.ToolHandlerMiddleware
+
+	chains         chainsCache
+	toolFilter     toolFilter
+	toolLimiter    *toolLimiter
+	toolTimeouts   []ToolTimeout
+	checksumMode   ChecksumMode
+	maxFeePercent  float64
+	rpcOverrides   map[int]string
+	tokenCache     *tokenCache
+	tokenCacheFile string
+	tokenIndex     tokenIndex
+	chainsCacheTTL time.Duration
+	stopRefresh    chan struct{}
+	closeOnce      sync.Once
+}
+
+// Option configures optional Server settings
+type Option func(*Server)
+
+// WithRateLimit sets the client-side limit on LI.FI API requests per period
+func WithRateLimit(limit int, period time.Duration) Option {
+	return func(s *Server) {
+		s.httpClient = NewHTTPClientWithRateLimit(s.logger, limit, period)
+	}
+}
+
+// WithChainsCacheTTL sets how long chain data is cached before it is refreshed.
+// Zero disables expiry and the background refresh.
+func WithChainsCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.chainsCacheTTL = ttl
+	}
+}
+
+// WithTokenCacheFile persists token metadata (symbol, decimals) to the given JSON file
+// so it survives restarts. By default the cache is in-memory only.
+func WithTokenCacheFile(path string) Option {
+	return func(s *Server) {
+		s.tokenCacheFile = path
+	}
+}
+
+// WithRPCOverrides sets preferred RPC URLs by chain ID (e.g. authenticated Alchemy or
+// Infura endpoints). They take precedence over the public RPCs from chain data, but
+// not over an rpcUrl passed to a tool.
+func WithRPCOverrides(overrides map[int]string) Option {
+	return func(s *Server) {
+		s.rpcOverrides = overrides
+	}
+}
+
+// WithExternalHTTPClient sets the client used for third-party requests (NFT metadata,
+// 4-byte signature lookups). LI.FI API requests keep their own rate-limited client.
+func WithExternalHTTPClient(client *http.Client) Option {
+	return func(s *Server) {
+		s.externalHTTP = client
+	}
+}
+
+// WithAPIHTTPClient sets the http.Client that LI.FI API requests are sent with, inside
+// the server's rate limiting and retries. Its Transport can point requests at a mock
+// LI.FI API in tests.
+func WithAPIHTTPClient(client *http.Client) Option {
+	return func(s *Server) {
+		s.apiHTTP = client
+	}
+}
+
+// WithToolFilter limits the registered tools using glob patterns on tool names
+// (e.g. "get-*"). With no enable patterns every tool is enabled; tools matching a
+// disable pattern are always left out.
+func WithToolFilter(enable, disable []string) Option {
+	return func(s *Server) {
+		s.toolFilter = toolFilter{enable: enable, disable: disable}
+	}
+}
+
+// WithToolLimits sets per-session call budgets on tools, e.g. at most 20 calls per day
+// to "transfer-nft". Calls over a budget fail with a tool error until the window frees up.
+func WithToolLimits(limits []ToolLimit) Option {
+	return func(s *Server) {
+		if len(limits) > 0 {
+			s.toolLimiter = newToolLimiter(limits)
+		}
+	}
+}
+
+// WithMaxFeePercent sets the share of a quote's USD value that fees and gas may take
+// before the quote carries a feeWarning. Zero disables the check.
+func WithMaxFeePercent(percent float64) Option {
+	return func(s *Server) {
+		s.maxFeePercent = percent
+	}
+}
+
+// WithToolTimeouts bounds tool calls by name pattern, e.g. "get-*" at 15s. The first
+// matching pattern wins; tools matching none keep the defaults: DefaultToolTimeout, with
+// more for track-transfer and wait-for-receipt. A zero timeout leaves a tool unbounded.
+func WithToolTimeouts(timeouts []ToolTimeout) Option {
+	return func(s *Server) {
+		s.toolTimeouts = timeouts
+	}
+}
+
+// WithAddressChecksum sets how EIP-55 checksums of address arguments are enforced.
+// The default, ChecksumMixedCase, rejects mixed-case addresses with a wrong checksum.
+func WithAddressChecksum(mode ChecksumMode) Option {
+	return func(s *Server) {
+		s.checksumMode = mode
+	}
+}
+
+// Options configures a Server created with New. Zero values select the defaults.
+type Options struct {
+	// Version is reported to MCP clients during initialization
+	Version string
+	// Logger defaults to slog.Default()
+	Logger *slog.Logger
+	// RateLimit and RatePeriod bound LI.FI API requests (see DefaultRateLimit)
+	RateLimit  int
+	RatePeriod time.Duration
+	// ChainsCacheTTL defaults to DefaultChainsCacheTTL; a negative value disables expiry
+	ChainsCacheTTL time.Duration
+	// TokenCacheFile persists token metadata across restarts when set
+	TokenCacheFile string
+	// RPCOverrides maps chain IDs to preferred RPC URLs
+	RPCOverrides map[int]string
+	// ExternalHTTPClient is used for third-party requests (NFT metadata, signature lookups)
+	ExternalHTTPClient *http.Client
+	// APIHTTPClient sends LI.FI API requests (see WithAPIHTTPClient)
+	APIHTTPClient *http.Client
+	// EnableTools and DisableTools select the exposed tools by name pattern (see WithToolFilter)
+	EnableTools  []string
+	DisableTools []string
+	// ToolLimits sets per-session call budgets by tool name pattern (see WithToolLimits)
+	ToolLimits []ToolLimit
+	// ToolTimeouts bounds tool calls by name pattern (see WithToolTimeouts)
+	ToolTimeouts []ToolTimeout
+	// AddressChecksum defaults to ChecksumMixedCase
+	AddressChecksum ChecksumMode
+	// MaxFeePercent defaults to DefaultMaxFeePercent; a negative value disables fee warnings
+	MaxFeePercent float64
+}
+
+// New creates a server for embedding in another Go program. Tools can be called
+// in-process with CallTool, or served over any mcp-go transport via GetMCPServer.
+func New(opts Options) *Server {
+	options := []Option{
+		WithRateLimit(opts.RateLimit, opts.RatePeriod),
+		WithTokenCacheFile(opts.TokenCacheFile),
+		WithRPCOverrides(opts.RPCOverrides),
+		WithToolFilter(opts.EnableTools, opts.DisableTools),
+		WithToolLimits(opts.ToolLimits),
+		WithToolTimeouts(opts.ToolTimeouts),
+	}
+	switch {
+	case opts.ChainsCacheTTL < 0:
+		options = append(options, WithChainsCacheTTL(0))
+	case opts.ChainsCacheTTL > 0:
+		options = append(options, WithChainsCacheTTL(opts.ChainsCacheTTL))
+	}
+	if opts.ExternalHTTPClient != nil {
+		options = append(options, WithExternalHTTPClient(opts.ExternalHTTPClient))
+	}
+	if opts.APIHTTPClient != nil {
+		options = append(options, WithAPIHTTPClient(opts.APIHTTPClient))
+	}
+	if opts.AddressChecksum != "" {
+		options = append(options, WithAddressChecksum(opts.AddressChecksum))
+	}
+	switch {
+	case opts.MaxFeePercent < 0:
+		options = append(options, WithMaxFeePercent(0))
+	case opts.MaxFeePercent > 0:
+		options = append(options, WithMaxFeePercent(opts.MaxFeePercent))
+	}
+	return NewServer(opts.Version, opts.Logger, options...)
 }
 
 // NewServer creates a new LiFi MCP server instance
-func NewServer(version string, logger *slog.Logger) *Server {
+func NewServer(version string, logger *slog.Logger, opts ...Option) *Server {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
 	s := &Server{
-		version:    version,
-		httpClient: NewHTTPClient(logger),
-		logger:     logger,
+		version:      version,
+		httpClient:   NewHTTPClient(logger),
+		externalHTTP: &http.Client{Timeout: 10 * time.Second},
+		rpcClients:   newRPCPool(logger),
+		logger:       logger,
+
+		chainsCacheTTL: DefaultChainsCacheTTL,
+		checksumMode:   ChecksumMixedCase,
+		maxFeePercent:  DefaultMaxFeePercent,
+		stopRefresh:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.apiHTTP != nil {
+		s.httpClient.client = s.apiHTTP
+	}
+	s.tokenCache = newTokenCache(defaultTokenCacheSize, s.tokenCacheFile, logger)
+	if s.chainsCacheTTL > 0 {
+		go s.refreshChainsPeriodically(s.stopRefresh)
 	}
 
 	// Create the MCP server
-	s.mcpServer = mcpserver.NewMCPServer(
-		"lifi-mcp",
-		version,
-	)
+	s.middlewares = []mcpserver.ToolHandlerMiddleware{
+		s.logToolCalls,
+		s.limitToolCalls,
+		s.enforceToolTimeouts,
+		s.normalizeChainArgs,
+		s.checksumAddresses,
+		s.resolveTokenSymbols,
+	}
+	serverOpts := make([]mcpserver.ServerOption, 0, len(s.middlewares))
+	for _, mw := range s.middlewares {
+		serverOpts = append(serverOpts, mcpserver.WithToolHandlerMiddleware(mw))
+	}
+	s.mcpServer = mcpserver.NewMCPServer("lifi-mcp", version, serverOpts...)
 
-	// Register tools
+	// Register tools, resources and prompts
 	s.registerTools()
+	s.registerResources()
+	s.registerPrompts()
+
+	if unmatched := s.toolFilter.unmatched(); len(unmatched) > 0 {
+		logger.Warn("Tool filter patterns match no tools", "patterns", unmatched)
+	}
+	if len(s.tools) == 0 {
+		logger.Warn("All tools are disabled by the tool filter")
+	}
 
 	return s
 }
@@ -52,6 +277,73 @@ func (s *Server) GetMCPServer() *mcpserver.MCPServer {
 	return s.mcpServer
 }
 
+// Tools returns the registered tools with their handlers wrapped in the server's
+// middleware (chain and token normalization, logging), ready to call in-process
+func (s *Server) Tools() []mcpserver.ServerTool {
+	tools := make([]mcpserver.ServerTool, len(s.tools))
+	for i, t := range s.tools {
+		tools[i] = mcpserver.ServerTool{Tool: t.Tool, Handler: s.wrapMiddleware(t.Handler)}
+	}
+	return tools
+}
+
+// CallTool calls a tool by name in-process, without an MCP transport. The LI.FI API
+// key is taken from ctx; use StaticAPIKey to attach one.
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	for _, t := range s.tools {
+		if t.Tool.Name != name {
+			continue
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = args
+		return s.wrapMiddleware(t.Handler)(ctx, request)
+	}
+	return nil, fmt.Errorf("unknown tool %q", name)
+}
+
+// wrapMiddleware applies the tool middleware in the same order as the MCP server
+func (s *Server) wrapMiddleware(handler mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](handler)
+	}
+	return handler
+}
+
+// toolAnnotations sets a tool's title and behavior hints. Every tool is read-only and
+// idempotent: none signs or sends transactions, and the transaction builders only
+// return unsigned transactionRequests. openWorld marks tools that reach the LI.FI API,
+// RPC nodes or other external services.
+func toolAnnotations(title string, openWorld bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	})
+}
+
+// addTool registers a tool with the MCP server and records it for in-process calls,
+// unless the tool filter leaves it out
+func (s *Server) addTool(tool mcp.Tool, handler mcpserver.ToolHandlerFunc) {
+	if !s.toolFilter.allows(tool.Name) {
+		return
+	}
+	s.tools = append(s.tools, mcpserver.ServerTool{Tool: tool, Handler: handler})
+	s.mcpServer.AddTool(tool, handler)
+}
+
+// Close stops background refreshes, writes pending token cache changes and releases
+// pooled RPC connections. The server must not be used afterwards.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stopRefresh)
+		s.rpcClients.close()
+		s.tokenCache.close()
+	})
+}
+
 // withPanicRecovery wraps a handler with panic recovery to prevent server crashes
 func (s *Server) withPanicRecovery(handler mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
@@ -73,73 +365,238 @@ func (s *Server) withPanicRecovery(handler mcpserver.ToolHandlerFunc) mcpserver.
 // registerTools registers all available tools with the MCP server
 func (s *Server) registerTools() {
 	// Health check tool for orchestration
-	s.mcpServer.AddTool(mcp.NewTool("health-check",
+	s.addTool(mcp.NewTool("health-check",
 		mcp.WithDescription("Check the health status of the LiFi MCP server. Returns server version and API connectivity status. Use this for health monitoring and orchestration."),
+		toolAnnotations("Server: Health check", true),
 	), s.withPanicRecovery(s.healthCheckHandler))
 
 	// LiFi API tools - Token Information
-	s.mcpServer.AddTool(mcp.NewTool("get-tokens",
-		mcp.WithDescription("Retrieve a list of all tokens supported by LI.FI across multiple chains. Use this to discover available tokens before executing swaps. Returns token addresses, symbols, decimals, and price information. Can filter by chain or minimum price to reduce response size."),
+	s.addTool(mcp.NewTool("get-tokens",
+		mcp.WithDescription("Retrieve a list of all tokens supported by LI.FI across multiple chains. Use this to discover available tokens before executing swaps. Returns token addresses, symbols, decimals, and price information, one page (50 tokens by default) at a time. Can filter by chain or minimum price to reduce response size."),
+		toolAnnotations("Tokens: List tokens", true),
 		mcp.WithString("chains", mcp.Description("Comma-separated chain IDs to filter tokens (e.g., '1,137,42161' for Ethereum, Polygon, Arbitrum). Omit for all chains.")),
 		mcp.WithString("chainTypes", mcp.Description("Filter by chain type: 'EVM' for Ethereum-compatible chains, 'SVM' for Solana. Comma-separated for multiple (e.g., 'EVM,SVM').")),
 		mcp.WithString("minPriceUSD", mcp.Description("Minimum token price in USD to filter out low-value tokens (e.g., '0.01' for tokens worth at least 1 cent).")),
+		mcp.WithNumber("limit", mcp.Description("Optional: Maximum number of tokens to return (default 50, max 1000).")),
+		mcp.WithNumber("offset", mcp.Description("Optional: Number of tokens to skip, for paging with page.nextOffset. Defaults to 0.")),
+		mcp.WithString("fields", mcp.Description("Optional: Comma-separated token fields to return. Defaults to address, symbol, decimals, name, priceUSD.")),
+		mcp.WithBoolean("full", mcp.Description("Optional: Return the complete, unpaginated LI.FI response. Can be megabytes; prefer limit/offset and fields.")),
 	), s.withPanicRecovery(s.getTokensHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-token",
+	s.addTool(mcp.NewTool("search-tokens",
+		mcp.WithDescription("Search the LI.FI token list by symbol or name with fuzzy matching and return a small ranked result set. A chain can be named in the query (e.g., 'bridged USDC on arbitrum') or passed as chain. Much cheaper than scanning get-tokens output: the token list is indexed locally and refreshed hourly."),
+		toolAnnotations("Tokens: Search tokens", true),
+		mcp.WithString("query", mcp.Description("Search text, e.g. 'usdc', 'wrapped bitcoin' or 'bridged USDC on arbitrum'."), mcp.Required()),
+		mcp.WithString("chain", mcp.Description("Optional: Restrict results to this chain, as numeric ID (e.g., '42161') or name (e.g., 'arbitrum').")),
+		mcp.WithNumber("limit", mcp.Description("Optional: Maximum number of results (default 10, max 50).")),
+	), s.withPanicRecovery(s.searchTokensHandler))
+
+	s.addTool(mcp.NewTool("get-token",
 		mcp.WithDescription("Get detailed information about a specific token including its address, symbol, decimals, and current price. Use this to verify token details before a swap or to look up a token by its symbol."),
+		toolAnnotations("Tokens: Get token", true),
 		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum, '137' for Polygon) or name (e.g., 'ethereum', 'polygon')."), mcp.Required()),
 		mcp.WithString("token", mcp.Description("Token identifier - either contract address (e.g., '0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48' for USDC) or symbol (e.g., 'USDC'). Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
 	), s.withPanicRecovery(s.getTokenHandler))
 
+	s.addTool(mcp.NewTool("get-token-prices",
+		mcp.WithDescription("Get current USD prices for a batch of tokens across chains in one call. Fetches the LI.FI token list once per set of chains and extracts priceUSD for each requested token. Use this to value a portfolio or compare token amounts without calling get-token repeatedly."),
+		toolAnnotations("Tokens: Get token prices", true),
+		mcp.WithArray("tokens", mcp.Description("Array of tokens to price (max 100). Each object needs: 'chain' (numeric chain ID, e.g., '1') and 'address' (token contract address, or '0x0000000000000000000000000000000000000000' for the native token)."), mcp.Required()),
+	), s.withPanicRecovery(s.getTokenPricesHandler))
+
+	s.addTool(mcp.NewTool("convert-to-usd",
+		mcp.WithDescription("Convert a token amount into USD, or a USD amount into the token, at the current LI.FI price. Works for native tokens and ERC20s. Use this to report the value of an amount or to work out how much of a token a dollar figure buys; the token amount is also returned in base units for use as fromAmount."),
+		toolAnnotations("Tokens: Convert to USD", true),
+		mcp.WithString("chain", mcp.Description("Chain ID or name (e.g., '1' or 'ethereum')."), mcp.Required()),
+		mcp.WithString("token", mcp.Description("Optional: Token address or symbol. Defaults to the chain's native token.")),
+		mcp.WithString("amount", mcp.Description("Human-readable token amount to value in USD (e.g., '1.5'). Provide this or usdAmount.")),
+		mcp.WithString("usdAmount", mcp.Description("USD amount to convert into the token (e.g., '250'). Provide this or amount. The token amount is rounded down.")),
+	), s.withPanicRecovery(s.convertToUSDHandler))
+
+	// Amount conversion tools
+	s.addTool(mcp.NewTool("format-token-amount",
+		mcp.WithDescription("Convert a token amount in base units (e.g., '1500000' for 1.5 USDC) into a human-readable decimal string. Decimals come from the 'decimals' argument or are looked up from chain and token. Use this to present fromAmount/toAmount values from quotes and balances."),
+		toolAnnotations("Amounts: Format token amount", true),
+		mcp.WithString("amount", mcp.Description("Amount in the token's smallest unit (integer string)."), mcp.Required()),
+		mcp.WithNumber("decimals", mcp.Description("Optional: Token decimals. If omitted, chain and token are used to look them up.")),
+		mcp.WithString("chain", mcp.Description("Optional: Chain ID or name, used with token to look up decimals.")),
+		mcp.WithString("token", mcp.Description("Optional: Token address or symbol, used with chain to look up decimals.")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL, used if the token is not known to LI.FI.")),
+	), s.withPanicRecovery(s.formatTokenAmountHandler))
+
+	s.addTool(mcp.NewTool("parse-token-amount",
+		mcp.WithDescription("Convert a human-readable token amount (e.g., '1.5') into base units for use as fromAmount in get-quote. Decimals come from the 'decimals' argument or are looked up from chain and token. ALWAYS use this instead of computing 10^decimals yourself."),
+		toolAnnotations("Amounts: Parse token amount", true),
+		mcp.WithString("amount", mcp.Description("Human-readable decimal amount (e.g., '1.5'). Must not have more decimal places than the token supports."), mcp.Required()),
+		mcp.WithNumber("decimals", mcp.Description("Optional: Token decimals. If omitted, chain and token are used to look them up.")),
+		mcp.WithString("chain", mcp.Description("Optional: Chain ID or name, used with token to look up decimals.")),
+		mcp.WithString("token", mcp.Description("Optional: Token address or symbol, used with chain to look up decimals.")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL, used if the token is not known to LI.FI.")),
+	), s.withPanicRecovery(s.parseTokenAmountHandler))
+
 	// LiFi API tools - Quote & Swap (Primary workflow tools)
-	s.mcpServer.AddTool(mcp.NewTool("get-quote",
+	s.addTool(mcp.NewTool("get-quote",
 		mcp.WithDescription("Get a quote for swapping or bridging tokens. This is the PRIMARY tool for initiating any token swap. Returns the best route including expected output amount, fees, estimated time, and a transactionRequest object. For ERC20 tokens, you may need to approve tokens first if allowance is insufficient."),
+		toolAnnotations("Quotes: Get quote", true),
 		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum, '137' for Polygon, '42161' for Arbitrum, '10' for Optimism)."), mcp.Required()),
 		mcp.WithString("toChain", mcp.Description("Destination chain ID. Use same as fromChain for same-chain swaps, different for cross-chain bridges."), mcp.Required()),
 		mcp.WithString("fromToken", mcp.Description("Source token address. Use '0x0000000000000000000000000000000000000000' for native tokens (ETH, MATIC, etc.) or the ERC20 contract address."), mcp.Required()),
 		mcp.WithString("toToken", mcp.Description("Destination token address. Use '0x0000000000000000000000000000000000000000' for native tokens or the ERC20 contract address on the destination chain."), mcp.Required()),
 		mcp.WithString("fromAddress", mcp.Description("Sender's wallet address (0x...). This address must have sufficient balance and approve ERC20 tokens if needed."), mcp.Required()),
-		mcp.WithString("fromAmount", mcp.Description("Amount to swap in the token's smallest unit (wei). For example, to swap 1 USDC (6 decimals), use '1000000'. For 1 ETH (18 decimals), use '1000000000000000000'."), mcp.Required()),
+		mcp.WithString("fromAmount", mcp.Description("Amount to swap in the token's smallest unit (wei). For example, to swap 1 USDC (6 decimals), use '1000000'. For 1 ETH (18 decimals), use '1000000000000000000'. Required unless amountHuman is given.")),
+		mcp.WithString("amountHuman", mcp.Description("Alternative to fromAmount: human-readable amount (e.g., '1.5'). Converted to base units using the fromToken's decimals; the conversion is echoed back as amountConversion.")),
 		mcp.WithString("toAddress", mcp.Description("Recipient's wallet address. Defaults to fromAddress if not specified. Use for sending tokens to a different address.")),
 		mcp.WithString("slippage", mcp.Description("Maximum acceptable slippage as a decimal (e.g., '0.03' for 3%, '0.005' for 0.5%). Higher values increase success rate but may result in worse rates.")),
 		mcp.WithString("integrator", mcp.Description("Your integrator identifier for tracking and fee sharing. Contact LI.FI for an integrator ID.")),
 		mcp.WithString("order", mcp.Description("Route optimization preference: 'RECOMMENDED' (balanced), 'FASTEST' (minimize time), 'CHEAPEST' (minimize fees), 'SAFEST' (most reliable bridges).")),
+		mcp.WithString("fromAmountForGas", mcp.Description("Optional: Part of fromAmount (in base units of fromToken) to convert into native gas on the destination chain (gas refuel), so the recipient can pay for transactions there. Use get-gas-suggestion to get a recommended value.")),
+		mcp.WithNumber("maxFeePercent", mcp.Description("Optional: Flag the quote with a feeWarning when fees and gas exceed this percentage of the amount's USD value (e.g., paying $12 to bridge $5). Defaults to the server's --max-fee-percent (10); 0 disables the check.")),
+		mcp.WithBoolean("summaryOnly", mcp.Description("Optional: Return a compact summary instead of the full quote: tool, amounts (base units and formatted), toAmountMin, total fee and gas costs in USD and estimated duration. Omits the route steps and transactionRequest; request the full quote to execute.")),
 		mcp.WithArray("allowBridges", mcp.Description("Whitelist specific bridges (e.g., ['stargate', 'hop', 'across']). Use get-tools to see available bridges.")),
 		mcp.WithArray("allowExchanges", mcp.Description("Whitelist specific DEXes (e.g., ['uniswap', 'sushiswap', '1inch']). Use get-tools to see available exchanges.")),
 	), s.withPanicRecovery(s.getQuoteHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-status",
+	s.addTool(mcp.NewTool("get-quote-to-amount",
+		mcp.WithDescription("Get an exact-output quote: how much fromToken must be sent to receive exactly toAmount of toToken (e.g., 'I need 500 USDC on Base, how much ETH on Ethereum do I send?'). Returns the same route, fees, estimated time and transactionRequest as get-quote, with the required input in estimate.fromAmount."),
+		toolAnnotations("Quotes: Get exact-output quote", true),
+		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum, '137' for Polygon, '42161' for Arbitrum, '10' for Optimism)."), mcp.Required()),
+		mcp.WithString("toChain", mcp.Description("Destination chain ID. Use same as fromChain for same-chain swaps, different for cross-chain bridges."), mcp.Required()),
+		mcp.WithString("fromToken", mcp.Description("Source token address. Use '0x0000000000000000000000000000000000000000' for native tokens (ETH, MATIC, etc.) or the ERC20 contract address."), mcp.Required()),
+		mcp.WithString("toToken", mcp.Description("Destination token address. Use '0x0000000000000000000000000000000000000000' for native tokens or the ERC20 contract address on the destination chain."), mcp.Required()),
+		mcp.WithString("fromAddress", mcp.Description("Sender's wallet address (0x...). This address must have sufficient balance and approve ERC20 tokens if needed."), mcp.Required()),
+		mcp.WithString("toAmount", mcp.Description("Amount to receive in toToken's smallest unit (e.g., '500000000' for 500 USDC). Required unless amountHuman is given.")),
+		mcp.WithString("amountHuman", mcp.Description("Alternative to toAmount: human-readable amount to receive (e.g., '500'). Converted to base units using the toToken's decimals; the conversion is echoed back as amountConversion.")),
+		mcp.WithString("toAddress", mcp.Description("Recipient's wallet address. Defaults to fromAddress if not specified. Use for sending tokens to a different address.")),
+		mcp.WithString("slippage", mcp.Description("Maximum acceptable slippage as a decimal (e.g., '0.03' for 3%, '0.005' for 0.5%). Higher values increase success rate but may result in worse rates.")),
+		mcp.WithString("integrator", mcp.Description("Your integrator identifier for tracking and fee sharing. Contact LI.FI for an integrator ID.")),
+		mcp.WithString("order", mcp.Description("Route optimization preference: 'RECOMMENDED' (balanced), 'FASTEST' (minimize time), 'CHEAPEST' (minimize fees), 'SAFEST' (most reliable bridges).")),
+		mcp.WithArray("allowBridges", mcp.Description("Whitelist specific bridges (e.g., ['stargate', 'hop', 'across']). Use get-tools to see available bridges.")),
+		mcp.WithArray("allowExchanges", mcp.Description("Whitelist specific DEXes (e.g., ['uniswap', 'sushiswap', '1inch']). Use get-tools to see available exchanges.")),
+		mcp.WithNumber("maxFeePercent", mcp.Description("Optional: Flag the quote with a feeWarning when fees and gas exceed this percentage of the amount's USD value (e.g., paying $12 to bridge $5). Defaults to the server's --max-fee-percent (10); 0 disables the check.")),
+		mcp.WithBoolean("summaryOnly", mcp.Description("Optional: Return a compact summary instead of the full quote: tool, amounts (base units and formatted), toAmountMin, total fee and gas costs in USD and estimated duration. Omits the route steps and transactionRequest; request the full quote to execute.")),
+	), s.withPanicRecovery(s.getQuoteToAmountHandler))
+
+	s.addTool(mcp.NewTool("get-status",
 		mcp.WithDescription("Check the status of an in-progress or completed cross-chain transfer. Use this to track bridge transactions which can take minutes to hours. Returns status (PENDING, DONE, FAILED), source/destination transaction hashes, and any error messages."),
+		toolAnnotations("Transfers: Get transfer status", true),
 		mcp.WithString("txHash", mcp.Description("The transaction hash from the source chain."), mcp.Required()),
 		mcp.WithString("bridge", mcp.Description("Bridge name used for the transfer (e.g., 'stargate', 'hop'). Speeds up status lookup if known.")),
 		mcp.WithString("fromChain", mcp.Description("Source chain ID. Helps identify the correct transaction if txHash exists on multiple chains.")),
 		mcp.WithString("toChain", mcp.Description("Destination chain ID. Required for some bridges to track the receiving transaction.")),
 	), s.withPanicRecovery(s.getStatusHandler))
 
+	s.addTool(mcp.NewTool("track-transfer",
+		mcp.WithDescription("Follow a cross-chain transfer until it completes. Polls the LI.FI status endpoint with backoff until the transfer reaches DONE, FAILED or INVALID, or until the timeout expires, sending MCP progress notifications on every poll when the client provides a progress token. Returns the final status response and, once DONE, the recipient's native gas balance on the destination chain (destinationGas) so a wallet stranded without gas is spotted. Pass the quote's toAmountMin to verify the received amount. Use this instead of calling get-status in a loop."),
+		toolAnnotations("Transfers: Track transfer", true),
+		mcp.WithString("txHash", mcp.Description("The transaction hash from the source chain."), mcp.Required()),
+		mcp.WithString("bridge", mcp.Description("Bridge name used for the transfer (e.g., 'stargate', 'hop'). Speeds up status lookup if known.")),
+		mcp.WithString("fromChain", mcp.Description("Source chain ID. Helps identify the correct transaction if txHash exists on multiple chains.")),
+		mcp.WithString("toChain", mcp.Description("Destination chain ID. Required for some bridges to track the receiving transaction.")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Source-chain RPC endpoint. When set, the source transaction is first awaited on-chain (a reverted source transaction is reported immediately) before LI.FI is polled. ws:// and wss:// endpoints are notified of new blocks via subscription instead of polling.")),
+		mcp.WithNumber("timeoutSeconds", mcp.Description("Maximum time to wait in seconds. Defaults to 600, maximum 1800. On timeout the last seen status is returned with timedOut=true.")),
+		mcp.WithString("toAmountMin", mcp.Description("Optional: The quote's estimate.toAmountMin (base units). Once DONE, the received amount is checked against it and any shortfall reported in outputVerification.")),
+		mcp.WithString("toAmount", mcp.Description("Optional: The quote's estimate.toAmount (base units), used to report the realized slippage.")),
+		mcp.WithString("toToken", mcp.Description("Optional: The quoted destination token address. A transfer that delivered a different token (e.g. a refund) is flagged instead of compared.")),
+	), s.withPanicRecovery(s.trackTransferHandler))
+
+	s.addTool(mcp.NewTool("get-transfers",
+		mcp.WithDescription("List a wallet's past LI.FI transfers (bridges and swaps) from the analytics endpoint, optionally filtered by time range, status and source/destination chain. Returns the transfers with their sending/receiving legs and a count per status, e.g. to answer \"what bridges did I do last week and did they all complete?\""),
+		toolAnnotations("Transfers: List transfers", true),
+		mcp.WithString("wallet", mcp.Description("Wallet address that sent the transfers."), mcp.Required()),
+		mcp.WithString("status", mcp.Description("Optional: Only return transfers with this status: ALL (default), DONE, PENDING or FAILED.")),
+		mcp.WithString("fromTimestamp", mcp.Description("Optional: Start of the time range as a unix timestamp in seconds, an RFC 3339 time or a YYYY-MM-DD date.")),
+		mcp.WithString("toTimestamp", mcp.Description("Optional: End of the time range, in the same formats as fromTimestamp.")),
+		mcp.WithString("fromChain", mcp.Description("Optional: Only return transfers sent from this chain ID.")),
+		mcp.WithString("toChain", mcp.Description("Optional: Only return transfers received on this chain ID.")),
+		mcp.WithString("integrator", mcp.Description("Optional: Only return transfers made through this integrator.")),
+	), s.withPanicRecovery(s.getTransfersHandler))
+
+	s.addTool(mcp.NewTool("find-pending-bridges",
+		mcp.WithDescription("Find a wallet's bridge transfers that are still in flight. Lists the transfers LI.FI analytics reports as PENDING since a point in time (default: the last 7 days) and rechecks each against /v1/status, so transfers that have completed since are reported under resolved instead. Use this to pick up unfinished transfers after losing the session that started them."),
+		toolAnnotations("Transfers: Find pending bridges", true),
+		mcp.WithString("wallet", mcp.Description("Wallet address that sent the transfers."), mcp.Required()),
+		mcp.WithString("since", mcp.Description("Optional: Only consider transfers sent after this time: a unix timestamp in seconds, an RFC 3339 time or a YYYY-MM-DD date. Defaults to 7 days ago.")),
+		mcp.WithString("fromChain", mcp.Description("Optional: Only consider transfers sent from this chain ID.")),
+		mcp.WithString("integrator", mcp.Description("Optional: Only consider transfers made through this integrator.")),
+	), s.withPanicRecovery(s.findPendingBridgesHandler))
+
 	// LiFi API tools - Chain Information
-	s.mcpServer.AddTool(mcp.NewTool("get-chains",
-		mcp.WithDescription("Get a list of all blockchain networks supported by LI.FI. Returns chain IDs, names, native tokens, RPC URLs, and block explorer URLs. Use this to discover available chains or get RPC URLs for blockchain interactions."),
+	s.addTool(mcp.NewTool("get-chains",
+		mcp.WithDescription("Get a list of all blockchain networks supported by LI.FI. Returns chain IDs, names and native tokens by default; request the metamask field for RPC URLs and block explorer URLs. Use this to discover available chains or get RPC URLs for blockchain interactions."),
+		toolAnnotations("Chains: List chains", true),
 		mcp.WithString("chainTypes", mcp.Description("Filter by chain type: 'EVM' for Ethereum-compatible chains (Ethereum, Polygon, Arbitrum, etc.), 'SVM' for Solana. Comma-separated for multiple.")),
+		mcp.WithNumber("limit", mcp.Description("Optional: Maximum number of chains to return (default 50, max 1000).")),
+		mcp.WithNumber("offset", mcp.Description("Optional: Number of chains to skip, for paging with page.nextOffset. Defaults to 0.")),
+		mcp.WithString("fields", mcp.Description("Optional: Comma-separated chain fields to return. Defaults to id, key, name, chainType, nativeToken (add metamask for RPC and explorer URLs).")),
+		mcp.WithBoolean("full", mcp.Description("Optional: Return the complete, unpaginated LI.FI response. Can be megabytes; prefer limit/offset and fields.")),
 	), s.withPanicRecovery(s.getChainsHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-connections",
-		mcp.WithDescription("Discover which token pairs can be swapped between chains. Use this to check if a specific swap route exists before calling get-quote. Returns available bridges and their supported tokens for the specified route."),
+	s.addTool(mcp.NewTool("get-connections",
+		mcp.WithDescription("Discover which token pairs can be swapped between chains. Use this to check if a specific swap route exists before calling get-quote. Returns available bridges and their supported tokens for the specified route, paginated per connection."),
+		toolAnnotations("Routing: List connections", true),
 		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum). Omit to see connections from all chains.")),
 		mcp.WithString("toChain", mcp.Description("Destination chain ID. Omit to see connections to all chains.")),
 		mcp.WithString("fromToken", mcp.Description("Source token address to filter connections for a specific token.")),
 		mcp.WithString("toToken", mcp.Description("Destination token address to filter for specific token pairs.")),
 		mcp.WithString("chainTypes", mcp.Description("Filter by chain type: 'EVM', 'SVM', or comma-separated combination.")),
 		mcp.WithArray("allowBridges", mcp.Description("Filter to show only specific bridges (e.g., ['stargate', 'hop']).")),
+		mcp.WithString("symbols", mcp.Description("Optional: Comma-separated token symbols (e.g., 'USDC,USDT'). Keeps only matching fromTokens and toTokens, and drops connections left without a pair.")),
+		mcp.WithBoolean("summaryOnly", mcp.Description("Optional: Return only the number of fromTokens and toTokens per connection instead of the token lists. Use this to explore which chains connect before listing tokens.")),
+		mcp.WithNumber("limit", mcp.Description("Optional: Maximum number of fromTokens and toTokens to return per connection (default 50, max 1000).")),
+		mcp.WithNumber("offset", mcp.Description("Optional: Number of tokens to skip, for paging with page.nextOffset. Defaults to 0.")),
+		mcp.WithString("fields", mcp.Description("Optional: Comma-separated token fields to return. Defaults to address, symbol, decimals.")),
+		mcp.WithBoolean("full", mcp.Description("Optional: Return the complete, unpaginated LI.FI response. Can be megabytes; prefer limit/offset and fields.")),
 	), s.withPanicRecovery(s.getConnectionsHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-tools",
+	s.addTool(mcp.NewTool("get-tools",
 		mcp.WithDescription("List all available bridges and DEX aggregators that LI.FI can route through. Use this to discover which protocols are available or to get bridge/exchange names for filtering in get-quote. Returns key (identifier to use in API calls) and name (human-readable)."),
+		toolAnnotations("Routing: List bridges and exchanges", true),
 		mcp.WithArray("chains", mcp.Description("Filter to show only tools available on specific chains (e.g., ['1', '137'] for Ethereum and Polygon).")),
 	), s.withPanicRecovery(s.getToolsHandler))
 
 	// LiFi API tools - Advanced Routing
-	s.mcpServer.AddTool(mcp.NewTool("get-routes",
+	s.addTool(mcp.NewTool("check-route-feasibility",
+		mcp.WithDescription("Check whether a transfer between two chains (and optionally a specific token pair) is possible at all before asking for quotes. Combines /v1/tools and /v1/connections to report feasible=true/false, the bridges that connect the chains (or the exchanges for a same-chain swap) and, with verifyBridges, which of those bridges carry the exact token pair. Use this to avoid wasted get-quote calls for impossible routes."),
+		toolAnnotations("Routing: Check route feasibility", true),
+		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum)."), mcp.Required()),
+		mcp.WithString("toChain", mcp.Description("Destination chain ID (e.g., '137' for Polygon)."), mcp.Required()),
+		mcp.WithString("fromToken", mcp.Description("Optional: Source token address or symbol.")),
+		mcp.WithString("toToken", mcp.Description("Optional: Destination token address or symbol.")),
+		mcp.WithBoolean("verifyBridges", mcp.Description("Optional: Query each candidate bridge (up to 15) for the token pair. Costs one API request per bridge. Defaults to false.")),
+	), s.withPanicRecovery(s.checkRouteFeasibilityHandler))
+
+	s.addTool(mcp.NewTool("compare-quotes",
+		mcp.WithDescription("Request quotes for the same transfer with different route preferences (RECOMMENDED, FASTEST, CHEAPEST) and optionally different bridge allow-lists, concurrently, and return a normalized comparison: tool, output amount, USD fee and gas costs, and estimated duration for each, plus which one has the highest output, is fastest and costs least. No transaction data is returned; call get-quote with the chosen order and bridges to execute."),
+		toolAnnotations("Quotes: Compare quotes", true),
+		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum)."), mcp.Required()),
+		mcp.WithString("toChain", mcp.Description("Destination chain ID (e.g., '137' for Polygon)."), mcp.Required()),
+		mcp.WithString("fromToken", mcp.Description("Source token contract address. Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
+		mcp.WithString("toToken", mcp.Description("Destination token contract address. Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
+		mcp.WithString("fromAddress", mcp.Description("Wallet address that will send the tokens."), mcp.Required()),
+		mcp.WithString("fromAmount", mcp.Description("Amount to transfer in base units (e.g., '1000000' for 1 USDC with 6 decimals)."), mcp.Required()),
+		mcp.WithString("toAddress", mcp.Description("Optional: Recipient address on the destination chain. Defaults to fromAddress.")),
+		mcp.WithString("slippage", mcp.Description("Optional: Maximum slippage as a decimal (e.g., '0.005' for 0.5%).")),
+		mcp.WithArray("orders", mcp.Description("Optional: Route preferences to compare (e.g., ['FASTEST', 'CHEAPEST']). Defaults to RECOMMENDED, FASTEST and CHEAPEST.")),
+		mcp.WithArray("bridgeSets", mcp.Description("Optional: Bridge allow-lists to compare (e.g., [['stargate'], ['across', 'hop']]). Each set is quoted with every order; at most 12 quotes in total.")),
+	), s.withPanicRecovery(s.compareQuotesHandler))
+
+	s.addTool(mcp.NewTool("analyze-price-impact",
+		mcp.WithDescription("Estimate price impact by quoting the same transfer at several fractions of the amount (default 1%, 10%, 50% and 100%) concurrently. Returns per-tranche exchange rates, the marginal rate of each step up in size and the impact against the best rate, and recommends whether to split the transfer. Each tranche costs one quote request."),
+		toolAnnotations("Quotes: Analyze price impact", true),
+		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum)."), mcp.Required()),
+		mcp.WithString("toChain", mcp.Description("Destination chain ID (e.g., '137' for Polygon)."), mcp.Required()),
+		mcp.WithString("fromToken", mcp.Description("Source token contract address. Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
+		mcp.WithString("toToken", mcp.Description("Destination token contract address. Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
+		mcp.WithString("fromAddress", mcp.Description("Wallet address that will send the tokens."), mcp.Required()),
+		mcp.WithString("fromAmount", mcp.Description("Full amount to transfer in base units (e.g., '1000000' for 1 USDC with 6 decimals)."), mcp.Required()),
+		mcp.WithString("slippage", mcp.Description("Optional: Maximum slippage as a decimal (e.g., '0.005' for 0.5%).")),
+		mcp.WithArray("fractions", mcp.Description("Optional: Tranche sizes as fractions of fromAmount in (0, 1] (e.g., [0.25, 0.5, 1]). At most 8.")),
+		mcp.WithString("splitThresholdPercent", mcp.Description("Optional: Price impact in percent above which splitting is recommended. Defaults to 1.")),
+	), s.withPanicRecovery(s.analyzePriceImpactHandler))
+
+	s.addTool(mcp.NewTool("get-routes",
 		mcp.WithDescription("Get multiple route options for a swap to compare alternatives. Unlike get-quote which returns the single best route, this returns several options ranked by the specified order preference. Useful when you want to show users multiple choices or when the best route fails. Use get-step-transaction to get executable transaction data for a chosen route."),
+		toolAnnotations("Quotes: Get routes", true),
 		mcp.WithString("fromChainId", mcp.Description("Source chain ID (e.g., '1' for Ethereum, '137' for Polygon)."), mcp.Required()),
 		mcp.WithString("toChainId", mcp.Description("Destination chain ID for cross-chain swaps, or same as fromChainId for same-chain."), mcp.Required()),
 		mcp.WithString("fromTokenAddress", mcp.Description("Source token contract address. Use '0x0000000000000000000000000000000000000000' for native tokens."), mcp.Required()),
@@ -151,8 +608,9 @@ func (s *Server) registerTools() {
 		mcp.WithString("order", mcp.Description("How to rank routes: 'RECOMMENDED', 'FASTEST', 'CHEAPEST', or 'SAFEST'.")),
 	), s.withPanicRecovery(s.getRoutesHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-quote-with-calls",
+	s.addTool(mcp.NewTool("get-quote-with-calls",
 		mcp.WithDescription("Get a quote that includes custom smart contract calls on the destination chain (also known as 'Zaps'). This enables complex DeFi operations in a single transaction: bridge tokens AND deposit into a vault, stake in a protocol, or interact with any contract. The contract calls execute atomically after the bridge completes."),
+		toolAnnotations("Quotes: Get quote with contract calls", true),
 		mcp.WithString("fromChain", mcp.Description("Source chain ID (e.g., '1' for Ethereum)."), mcp.Required()),
 		mcp.WithString("toChain", mcp.Description("Destination chain ID where contract calls will execute."), mcp.Required()),
 		mcp.WithString("fromToken", mcp.Description("Source token address to bridge."), mcp.Required()),
@@ -163,61 +621,239 @@ func (s *Server) registerTools() {
 		mcp.WithString("slippage", mcp.Description("Maximum slippage as decimal (e.g., '0.03' for 3%).")),
 	), s.withPanicRecovery(s.getQuoteWithCallsHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-step-transaction",
+	s.addTool(mcp.NewTool("get-step-transaction",
 		mcp.WithDescription("Convert a route step from get-routes into executable transaction data. Use this when you've chosen a specific route from get-routes and need the transaction to sign and send. Returns the same transactionRequest format as get-quote."),
+		toolAnnotations("Quotes: Get step transaction", true),
 		mcp.WithObject("step", mcp.Description("A step object from the get-routes response. Pass the entire step object including its 'action', 'estimate', and other properties."), mcp.Required()),
 	), s.withPanicRecovery(s.getStepTransactionHandler))
 
 	// LiFi API tools - Gas Information
-	s.mcpServer.AddTool(mcp.NewTool("get-gas-prices",
+	s.addTool(mcp.NewTool("get-gas-prices",
 		mcp.WithDescription("Get current gas prices for all supported EVM chains. Returns fast/standard/slow gas prices in gwei. Useful for estimating transaction costs before executing swaps or for monitoring network congestion."),
+		toolAnnotations("Gas: Get gas prices", true),
 	), s.withPanicRecovery(s.getGasPricesHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-gas-suggestion",
-		mcp.WithDescription("Get recommended gas parameters for a specific chain including base fee, priority fee, and estimated costs. More detailed than get-gas-prices for a single chain. Useful when constructing transactions manually."),
-		mcp.WithString("chainId", mcp.Description("Chain ID to get gas suggestions for (e.g., '1' for Ethereum, '137' for Polygon)."), mcp.Required()),
+	s.addTool(mcp.NewTool("get-gas-suggestion",
+		mcp.WithDescription("Get a gas recommendation for a destination chain: how much native gas token to request when bridging there, and whether gas refuel is available. Pass fromChain and fromToken to get the recommended amount expressed in the token you are bridging from (use it as fromAmountForGas in get-quote)."),
+		toolAnnotations("Gas: Get gas suggestion", true),
+		mcp.WithString("chainId", mcp.Description("Destination chain ID to get the gas recommendation for (e.g., '1' for Ethereum, '137' for Polygon)."), mcp.Required()),
+		mcp.WithString("fromChain", mcp.Description("Optional: Source chain ID of the bridge. Required together with fromToken to get fromAmount.")),
+		mcp.WithString("fromToken", mcp.Description("Optional: Source token address of the bridge. The response then includes the fromAmount of this token needed to cover the recommended gas.")),
 	), s.withPanicRecovery(s.getGasSuggestionHandler))
 
+	s.addTool(mcp.NewTool("get-gas-price",
+		mcp.WithDescription("Get live fee data for one chain from its RPC: the next block's base fee and slow/standard/fast priority fees sampled from the last 20 blocks (eth_feeHistory), with the estimated and maximum cost of a transaction of the given gasLimit in the native token and USD. Chains without EIP-1559 report the legacy gas price as the base fee. Use this to reason about costs before executing a transaction."),
+		toolAnnotations("Gas: Get on-chain gas price", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithNumber("gasLimit", mcp.Description("Optional: Gas limit to price (e.g., the gasLimit of a transactionRequest). Defaults to 21000, a plain native transfer.")),
+	), s.withPanicRecovery(s.getGasPriceHandler))
+
+	// Block and chain head tools
+	s.addTool(mcp.NewTool("get-block",
+		mcp.WithDescription("Get a block header from the chain's RPC: number, hash, timestamp and age, transaction count, gas used vs. gas limit, and the base fee. Optionally lists the block's transaction hashes."),
+		toolAnnotations("Chains: Get block", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("block", mcp.Description("Optional: Block number (decimal or hex), block hash, or one of 'latest', 'pending', 'safe', 'finalized', 'earliest'. Defaults to 'latest'.")),
+		mcp.WithBoolean("includeTransactions", mcp.Description("Optional: Include the block's transaction hashes (up to 500). Defaults to false.")),
+	), s.withPanicRecovery(s.getBlockHandler))
+
+	s.addTool(mcp.NewTool("get-chain-head",
+		mcp.WithDescription("Check whether a chain is alive and how congested it is: the latest block with its age, base fee and gas utilization, the average block time, the safe and finalized blocks with their lag behind the head, and whether the RPC is syncing or stale. Use this before executing a transaction on a chain."),
+		toolAnnotations("Chains: Get chain head", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+	), s.withPanicRecovery(s.getChainHeadHandler))
+
 	// LiFi API tools - API Key Testing
-	s.mcpServer.AddTool(mcp.NewTool("test-api-key",
+	s.addTool(mcp.NewTool("test-api-key",
 		mcp.WithDescription("Test if the LI.FI API key provided in the request header is valid. Returns key status and rate limit information. Requires API key to be passed via Authorization header (Bearer token) or X-LiFi-Api-Key header."),
+		toolAnnotations("Server: Test API key", true),
 	), s.withPanicRecovery(s.testApiKeyHandler))
 
+	s.addTool(mcp.NewTool("get-rate-limit-status",
+		mcp.WithDescription("Get the state of the server's client-side rate limiter for LI.FI API requests: configured limit and period, requests currently available, and seconds until the next request is allowed. Also returns the remaining quota last reported by the LI.FI API for the caller's API key, if any. Use this to pace bulk lookups instead of hitting rate limit errors."),
+		toolAnnotations("Server: Get rate limit status", false),
+	), s.withPanicRecovery(s.getRateLimitStatusHandler))
+
 	// LiFi API tools - Chain Lookup
-	s.mcpServer.AddTool(mcp.NewTool("get-chain-by-id",
+	s.addTool(mcp.NewTool("get-chain-by-id",
 		mcp.WithDescription("Look up chain details by numeric chain ID. Returns chain name, native token info, RPC URLs, and block explorer. Use this to convert a chain ID to human-readable information or to get RPC URLs."),
+		toolAnnotations("Chains: Get chain by ID", true),
 		mcp.WithString("id", mcp.Description("Numeric chain ID (e.g., '1' for Ethereum, '137' for Polygon, '42161' for Arbitrum, '10' for Optimism, '56' for BSC)."), mcp.Required()),
 	), s.withPanicRecovery(s.getChainByIdHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-chain-by-name",
+	s.addTool(mcp.NewTool("get-chain-by-name",
 		mcp.WithDescription("Look up chain details by name or key. Performs case-insensitive matching against chain name, key, or ID. Use this when you know the chain name but need its ID or RPC URL."),
+		toolAnnotations("Chains: Get chain by name", true),
 		mcp.WithString("name", mcp.Description("Chain name (e.g., 'Ethereum', 'Polygon'), key (e.g., 'eth', 'pol'), or ID as string (e.g., '1')."), mcp.Required()),
 	), s.withPanicRecovery(s.getChainByNameHandler))
 
 	// Blockchain interaction tools - Balance & Allowance Queries (read-only, no signing required)
-	s.mcpServer.AddTool(mcp.NewTool("get-native-token-balance",
+	s.addTool(mcp.NewTool("get-native-token-balance",
 		mcp.WithDescription("Check the native token balance (ETH, MATIC, etc.) of any wallet address. Returns the balance in wei (smallest unit) along with the token symbol and decimals."),
+		toolAnnotations("Balances: Get native balance", true),
 		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum, '137' for Polygon) or name (e.g., 'ethereum', 'polygon'). The RPC URL is looked up automatically."), mcp.Required()),
 		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. If provided, overrides the default RPC for the chain. Use this if you have your own RPC endpoint (e.g., Alchemy, Infura).")),
 		mcp.WithString("address", mcp.Description("Wallet address to check balance for (0x... format, 42 characters)."), mcp.Required()),
 	), s.withPanicRecovery(s.getNativeTokenBalanceHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-token-balance",
+	s.addTool(mcp.NewTool("get-token-balance",
 		mcp.WithDescription("Check the ERC20 token balance of any wallet address. Returns the balance in the token's smallest unit along with symbol and decimals. Use this before swaps to verify sufficient balance."),
+		toolAnnotations("Balances: Get token balance", true),
 		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
 		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
 		mcp.WithString("tokenAddress", mcp.Description("ERC20 token contract address (0x... format). Get from get-token or get-tokens."), mcp.Required()),
 		mcp.WithString("walletAddress", mcp.Description("Wallet address to check balance for (0x... format)."), mcp.Required()),
 	), s.withPanicRecovery(s.getTokenBalanceHandler))
 
-	s.mcpServer.AddTool(mcp.NewTool("get-allowance",
+	s.addTool(mcp.NewTool("get-token-balances",
+		mcp.WithDescription("Check the balances of many ERC20 tokens for one wallet in a single RPC round-trip. Batches balanceOf, symbol and decimals for every token through Multicall3. Prefer this over repeated get-token-balance calls when checking more than one token."),
+		toolAnnotations("Balances: Get token balances", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithArray("tokenAddresses", mcp.Description("ERC20 token contract addresses to check (max 100, e.g., ['0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48'])."), mcp.Required()),
+		mcp.WithString("walletAddress", mcp.Description("Wallet address to check balances for (0x... format)."), mcp.Required()),
+	), s.withPanicRecovery(s.getTokenBalancesHandler))
+
+	s.addTool(mcp.NewTool("get-wallet-portfolio",
+		mcp.WithDescription("Scan a wallet's balances across several EVM chains in one call. For each chain, reads the native token and major ERC20 tokens (USDC, USDT, DAI, WETH, WBTC) in a single Multicall3 request, attaches LI.FI USD prices, and returns non-zero holdings with per-chain and total USD values. Chains are scanned concurrently; an unreachable chain is reported without failing the others."),
+		toolAnnotations("Balances: Get wallet portfolio", true),
+		mcp.WithString("address", mcp.Description("Wallet address to scan (0x... format)."), mcp.Required()),
+		mcp.WithString("chains", mcp.Description("Comma-separated chain IDs to scan (e.g., '1,137,42161'). Defaults to Ethereum, Optimism, BSC, Polygon, Base, Arbitrum and Avalanche.")),
+	), s.withPanicRecovery(s.getWalletPortfolioHandler))
+
+	s.addTool(mcp.NewTool("get-allowance",
 		mcp.WithDescription("Check how many ERC20 tokens a spender is approved to use on behalf of an owner. IMPORTANT: Before executing a swap with ERC20 tokens, verify the allowance is >= the swap amount. If insufficient, the user must approve tokens first. The spender address for LI.FI swaps is returned in the get-quote response."),
+		toolAnnotations("Allowances: Get allowance", true),
 		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
 		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
 		mcp.WithString("tokenAddress", mcp.Description("ERC20 token contract address to check allowance for."), mcp.Required()),
 		mcp.WithString("ownerAddress", mcp.Description("Wallet address that owns the tokens (the 'fromAddress' in swaps)."), mcp.Required()),
 		mcp.WithString("spenderAddress", mcp.Description("Contract address that would spend the tokens. For LI.FI swaps, use the address from transactionRequest.to in the get-quote response."), mcp.Required()),
 	), s.withPanicRecovery(s.getAllowanceHandler))
+
+	s.addTool(mcp.NewTool("get-token-approvals",
+		mcp.WithDescription("Scan the ERC20 allowances a wallet has granted on one chain and flag unlimited approvals. Checks the given tokens (default: major tokens such as USDC, USDT, DAI, WETH, WBTC) against the given spenders (default: the LI.FI Diamond and Permit2) in one Multicall3 call, and optionally discovers further approvals from the wallet's recent Approval logs. Use revoke-approval to remove any that are no longer needed."),
+		toolAnnotations("Allowances: List token approvals", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("owner", mcp.Description("Wallet address whose approvals to scan (0x...)."), mcp.Required()),
+		mcp.WithString("tokens", mcp.Description("Optional: Comma-separated ERC20 token addresses to check. Defaults to the chain's major tokens.")),
+		mcp.WithString("spenders", mcp.Description("Optional: Comma-separated spender addresses to check. Defaults to the LI.FI Diamond and Permit2.")),
+		mcp.WithNumber("lookbackBlocks", mcp.Description("Optional: Also scan the wallet's Approval logs over this many recent blocks (max 100000) to find other tokens and spenders. Many public RPCs limit log ranges; use a smaller value or an rpcUrl if this fails.")),
+	), s.withPanicRecovery(s.getTokenApprovalsHandler))
+
+	s.addTool(mcp.NewTool("revoke-approval",
+		mcp.WithDescription("Build an unsigned transaction that sets a wallet's ERC20 allowance for a spender to zero. Checks the current allowance first and returns alreadyRevoked=true (and no transaction) if it is already zero. The transactionRequest must be signed and broadcast with the owner's wallet."),
+		toolAnnotations("Allowances: Build approval revocation", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("owner", mcp.Description("Wallet address that granted the approval (0x...)."), mcp.Required()),
+		mcp.WithString("tokenAddress", mcp.Description("ERC20 token contract address (0x...)."), mcp.Required()),
+		mcp.WithString("spenderAddress", mcp.Description("Spender whose allowance to revoke (0x...)."), mcp.Required()),
+	), s.withPanicRecovery(s.revokeApprovalHandler))
+
+	s.addTool(mcp.NewTool("plan-approval",
+		mcp.WithDescription("Work out the ERC20 approval a quote (from get-quote) needs before it can be executed, without executing anything. Returns the spender (estimate.approvalAddress), the current and missing allowance, whether the wallet's balance covers fromAmount, whether the token supports EIP-2612 permit or is already approved to Permit2, and, when an approval is missing, the unsigned approve transactionRequests to sign first. Native tokens never need an approval."),
+		toolAnnotations("Allowances: Plan quote approval", true),
+		mcp.WithObject("quote", mcp.Description("The full quote object returned by get-quote (or a step from get-routes), with action and estimate."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL for the source chain. Overrides the default RPC for quote.action.fromChainId.")),
+	), s.withPanicRecovery(s.planApprovalHandler))
+
+	s.addTool(mcp.NewTool("get-nft-balance",
+		mcp.WithDescription("Get how many NFTs a wallet holds in an ERC-721 or ERC-1155 collection. The standard is detected via ERC-165. For ERC-721 without tokenId this is the number of tokens held in the collection; with tokenId it is 1 if the wallet owns that token and 0 otherwise. ERC-1155 balances are per token ID, so tokenId is required."),
+		toolAnnotations("NFTs: Get NFT balance", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("contractAddress", mcp.Description("NFT collection contract address (0x...)."), mcp.Required()),
+		mcp.WithString("ownerAddress", mcp.Description("Wallet address to check (0x...)."), mcp.Required()),
+		mcp.WithString("tokenId", mcp.Description("Optional for ERC-721, required for ERC-1155: token ID in decimal or 0x-prefixed hex.")),
+	), s.withPanicRecovery(s.getNftBalanceHandler))
+
+	s.addTool(mcp.NewTool("get-nft-owner",
+		mcp.WithDescription("Get the owner and metadata of an NFT. Returns the ERC-721 owner (ERC-1155 has no single owner), the collection name, the tokenURI and the metadata JSON it points to (http(s), ipfs:// via a public gateway, or data: URIs)."),
+		toolAnnotations("NFTs: Get NFT owner", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("contractAddress", mcp.Description("NFT collection contract address (0x...)."), mcp.Required()),
+		mcp.WithString("tokenId", mcp.Description("Token ID in decimal or 0x-prefixed hex."), mcp.Required()),
+		mcp.WithBoolean("includeMetadata", mcp.Description("Optional: Fetch the metadata JSON from the tokenURI. Defaults to true.")),
+	), s.withPanicRecovery(s.getNftOwnerHandler))
+
+	s.addTool(mcp.NewTool("transfer-nft",
+		mcp.WithDescription("Build an unsigned safeTransferFrom transaction for an ERC-721 or ERC-1155 token. Checks that fromAddress owns the token (or enough of an ERC-1155 ID) and estimates gas, which also catches recipients that cannot receive NFTs. The transactionRequest must be signed and broadcast with the fromAddress wallet."),
+		toolAnnotations("NFTs: Build NFT transfer", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("contractAddress", mcp.Description("NFT collection contract address (0x...)."), mcp.Required()),
+		mcp.WithString("fromAddress", mcp.Description("Current holder of the token (0x...)."), mcp.Required()),
+		mcp.WithString("toAddress", mcp.Description("Recipient address (0x...)."), mcp.Required()),
+		mcp.WithString("tokenId", mcp.Description("Token ID in decimal or 0x-prefixed hex."), mcp.Required()),
+		mcp.WithString("amount", mcp.Description("Optional: Number of ERC-1155 tokens to transfer. Defaults to 1; must be 1 for ERC-721.")),
+	), s.withPanicRecovery(s.transferNftHandler))
+
+	s.addTool(mcp.NewTool("wait-for-receipt",
+		mcp.WithDescription("Wait for a transaction to be mined and return its receipt. Polls the chain (or, for a ws:// or wss:// rpcUrl, subscribes to new blocks) until the transaction has the requested number of confirmations or the timeout expires. Returns status (success/reverted), gasUsed, effectiveGasPrice, the emitted logs and decoded ERC20 Transfer events. Use this after broadcasting a transactionRequest from get-quote with your wallet."),
+		toolAnnotations("Transactions: Wait for receipt", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("txHash", mcp.Description("The transaction hash to wait for (0x... format, 66 characters)."), mcp.Required()),
+		mcp.WithNumber("confirmations", mcp.Description("Number of confirmations to wait for, including the block the transaction was mined in. Defaults to 1.")),
+		mcp.WithNumber("timeoutSeconds", mcp.Description("Maximum time to wait in seconds. Defaults to 120, maximum 600.")),
+	), s.withPanicRecovery(s.waitForReceiptHandler))
+
+	s.addTool(mcp.NewTool("get-transaction",
+		mcp.WithDescription("Fetch a transaction by hash. Returns from, to, value, nonce, gas and fee fields, the raw input and its 4-byte function selector, and whether it is still pending. Use this to verify what was actually broadcast."),
+		toolAnnotations("Transactions: Get transaction", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("txHash", mcp.Description("The transaction hash (0x... format, 66 characters)."), mcp.Required()),
+	), s.withPanicRecovery(s.getTransactionHandler))
+
+	s.addTool(mcp.NewTool("get-receipt",
+		mcp.WithDescription("Fetch the receipt of a mined transaction without waiting. Returns status (success/reverted), gasUsed, effectiveGasPrice, the emitted logs and decoded ERC20 Transfer events. Use wait-for-receipt instead if the transaction may still be pending."),
+		toolAnnotations("Transactions: Get receipt", true),
+		mcp.WithString("chain", mcp.Description("Chain identifier - either numeric ID (e.g., '1' for Ethereum) or name (e.g., 'ethereum'). The RPC URL is looked up automatically."), mcp.Required()),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Overrides the default RPC for the chain.")),
+		mcp.WithString("txHash", mcp.Description("The transaction hash (0x... format, 66 characters)."), mcp.Required()),
+	), s.withPanicRecovery(s.getReceiptHandler))
+
+	s.addTool(mcp.NewTool("simulate-transaction",
+		mcp.WithDescription("Simulate a transactionRequest (e.g., from get-quote) against the latest block without broadcasting it. Uses debug_traceCall when the RPC supports it to return the full call trace, emitted logs, ERC20 transfers and the sender's net balance changes; otherwise falls back to eth_call with gas estimation. Returns success, gasUsed and the decoded revert reason on failure. Use this to validate a quote before signing it."),
+		toolAnnotations("Transactions: Simulate transaction", true),
+		mcp.WithObject("transactionRequest", mcp.Description("The transactionRequest object to simulate, with 'from', 'to', 'data', and optional 'value', 'gasLimit' and 'chainId' (hex or decimal strings)."), mcp.Required()),
+		mcp.WithString("chain", mcp.Description("Optional: Chain identifier - numeric ID or name. Defaults to transactionRequest.chainId.")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom RPC endpoint URL. Use an RPC with the debug namespace enabled for full traces.")),
+	), s.withPanicRecovery(s.simulateTransactionHandler))
+
+	s.addTool(mcp.NewTool("decode-calldata",
+		mcp.WithDescription("Decode transactionRequest.data (e.g., from get-quote) against the LI.FI Diamond ABI. With lookupSignature, selectors of other contracts are looked up on 4byte.directory, an external request to a third-party service. Returns the function, its decoded arguments and, for bridge calls, the bridge, receiver, minimum amount, destination chain, source swaps and whether a destination call is attached. Use this to verify what a quote will do before signing it."),
+		toolAnnotations("Transactions: Decode calldata", true),
+		mcp.WithString("data", mcp.Description("The calldata to decode (0x-prefixed hex, at least the 4-byte selector)."), mcp.Required()),
+		mcp.WithString("to", mcp.Description("Optional: The transaction's 'to' address. Reported as toIsLiFiDiamond when it matches the LI.FI Diamond.")),
+		mcp.WithBoolean("lookupSignature", mcp.Description("Optional: Look up unknown selectors on 4byte.directory. This sends the selector to that third-party service (default: false).")),
+		mcp.WithString("expectedReceiver", mcp.Description("Optional: The address that should receive the funds. Reports receiverMatches and warns when the receiver encoded in the calldata differs.")),
+	), s.withPanicRecovery(s.decodeCalldataHandler))
+
+	// Solana (SVM) tools - Balance Queries
+	s.addTool(mcp.NewTool("get-solana-balance",
+		mcp.WithDescription("Check the native SOL balance of a Solana wallet. Returns the balance in lamports (1 SOL = 1,000,000,000 lamports) along with the slot it was read at."),
+		toolAnnotations("Solana: Get SOL balance", true),
+		mcp.WithString("address", mcp.Description("Solana wallet address (base58, e.g., '9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM')."), mcp.Required()),
+		mcp.WithString("chain", mcp.Description("Optional: Chain identifier used to look up the RPC URL. Defaults to 'sol' (Solana mainnet).")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom Solana RPC endpoint URL. Overrides the default RPC.")),
+	), s.withPanicRecovery(s.getSolanaBalanceHandler))
+
+	s.addTool(mcp.NewTool("get-spl-token-balance",
+		mcp.WithDescription("Check the SPL token balance of a Solana wallet for a given mint. Sums all token accounts the wallet holds for that mint and returns the balance in the token's smallest unit with decimals."),
+		toolAnnotations("Solana: Get SPL token balance", true),
+		mcp.WithString("mintAddress", mcp.Description("SPL token mint address (base58, e.g., 'EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v' for USDC). Get from get-token with chain 'sol'."), mcp.Required()),
+		mcp.WithString("walletAddress", mcp.Description("Solana wallet address that owns the token accounts (base58)."), mcp.Required()),
+		mcp.WithString("chain", mcp.Description("Optional: Chain identifier used to look up the RPC URL. Defaults to 'sol' (Solana mainnet).")),
+		mcp.WithString("rpcUrl", mcp.Description("Optional: Custom Solana RPC endpoint URL. Overrides the default RPC.")),
+	), s.withPanicRecovery(s.getSplTokenBalanceHandler))
 }
 
 // Chain data structures
@@ -229,6 +865,7 @@ type Chain struct {
 	ID             int          `json:"id"`
 	Key            string       `json:"key"`
 	Name           string       `json:"name"`
+	ChainType      string       `json:"chainType"`
 	NativeToken    Token        `json:"nativeToken"`
 	NativeCurrency Token        `json:"nativeCurrency"`
 	Metamask       MetamaskInfo `json:"metamask"`
@@ -248,12 +885,15 @@ type Token struct {
 	Name     string `json:"name"`
 }
 
-// Cache for chain data with mutex protection
-var (
-	chainsCache            ChainData
-	chainsCacheInitialized bool
-	chainsCacheMu          sync.RWMutex
-)
+// chainsCache holds chain data fetched from LI.FI with mutex protection. Concurrent
+// refreshes share one request through the singleflight group.
+type chainsCache struct {
+	mu          sync.RWMutex
+	data        ChainData
+	initialized bool
+	updatedAt   time.Time
+	refresh     singleflight.Group
+}
 
 // ERC20 ABI for token interactions
 const ERC20ABI = `[
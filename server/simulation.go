@@ -0,0 +1,328 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// erc20TransferTopic is keccak256("Transfer(address,address,uint256)")
+var erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// callFrame is a frame of the geth callTracer output
+type callFrame struct {
+	Type         string         `json:"type"`
+	From         string         `json:"from"`
+	To           string         `json:"to,omitempty"`
+	Value        *hexutil.Big   `json:"value,omitempty"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []callFrame    `json:"calls,omitempty"`
+	Logs         []callLog      `json:"logs,omitempty"`
+}
+
+// callLog is a log emitted inside a callTracer frame
+type callLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// tokenTransfer is a decoded ERC20 Transfer event
+type tokenTransfer struct {
+	Token  string `json:"token"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+// decodeERC20Transfer decodes an ERC20 Transfer log. ERC721 transfers share the
+// event signature but index the token ID, so they are rejected by the topic count.
+func decodeERC20Transfer(address common.Address, topics []common.Hash, data []byte) (tokenTransfer, bool) {
+	if len(topics) != 3 || topics[0] != erc20TransferTopic || len(data) != 32 {
+		return tokenTransfer{}, false
+	}
+	return tokenTransfer{
+		Token:  address.Hex(),
+		From:   common.BytesToAddress(topics[1].Bytes()).Hex(),
+		To:     common.BytesToAddress(topics[2].Bytes()).Hex(),
+		Amount: new(big.Int).SetBytes(data).String(),
+	}, true
+}
+
+// parseQuantity parses a hex (0x-prefixed) or decimal integer string
+func parseQuantity(value string) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return new(big.Int), nil
+	}
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		n, ok := new(big.Int).SetString(value[2:], 16)
+		if !ok && value[2:] != "" {
+			return nil, fmt.Errorf("invalid hex quantity: %s", value)
+		}
+		if n == nil {
+			n = new(big.Int)
+		}
+		return n, nil
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity: %s", value)
+	}
+	return n, nil
+}
+
+// transactionRequestArgs is the subset of a LI.FI transactionRequest needed to simulate it
+type transactionRequestArgs struct {
+	From     string
+	To       string
+	Data     string
+	Value    string
+	GasLimit string
+	ChainID  string
+}
+
+// parseTransactionRequest reads a transactionRequest object as returned by get-quote
+func parseTransactionRequest(obj map[string]interface{}) transactionRequestArgs {
+	str := func(key string) string {
+		switch v := obj[key].(type) {
+		case string:
+			return v
+		case float64:
+			return new(big.Float).SetFloat64(v).Text('f', 0)
+		}
+		return ""
+	}
+	return transactionRequestArgs{
+		From:     str("from"),
+		To:       str("to"),
+		Data:     str("data"),
+		Value:    str("value"),
+		GasLimit: str("gasLimit"),
+		ChainID:  str("chainId"),
+	}
+}
+
+func (s *Server) simulateTransactionHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	txObj := getObjectArg(request, "transactionRequest")
+	if txObj == nil {
+		return mcp.NewToolResultError("transactionRequest object is required"), nil
+	}
+	tx := parseTransactionRequest(txObj)
+
+	if err := ValidateAddress("transactionRequest.from", tx.From); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateAddress("transactionRequest.to", tx.To); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	data, err := hexutil.Decode(tx.Data)
+	if tx.Data != "" && err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("transactionRequest.data: invalid hex: %v", err)), nil
+	}
+	value, err := parseQuantity(tx.Value)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("transactionRequest.value: %v", err)), nil
+	}
+	gasLimit, err := parseQuantity(tx.GasLimit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("transactionRequest.gasLimit: %v", err)), nil
+	}
+
+	// Fall back to the chain ID embedded in the transactionRequest
+	if chain == "" && rpcUrl == "" && tx.ChainID != "" {
+		chainID, err := parseQuantity(tx.ChainID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("transactionRequest.chainId: %v", err)), nil
+		}
+		chain = chainID.String()
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	from := common.HexToAddress(tx.From)
+	to := common.HexToAddress(tx.To)
+
+	// Prefer a full call trace; most public RPCs do not expose the debug namespace
+	callArgs := map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"input": hexutil.Bytes(data),
+		"value": (*hexutil.Big)(value),
+	}
+	if gasLimit.Sign() > 0 {
+		callArgs["gas"] = hexutil.Uint64(gasLimit.Uint64())
+	}
+
+	var trace callFrame
+	traceErr := client.Client().CallContext(ctx, &trace, "debug_traceCall", callArgs, "latest", map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]interface{}{"withLog": true},
+	})
+
+	responseData := map[string]interface{}{
+		"from": from.Hex(),
+		"to":   to.Hex(),
+	}
+
+	if traceErr == nil {
+		responseData["method"] = "debug_traceCall"
+		responseData["success"] = trace.Error == ""
+		responseData["gasUsed"] = uint64(trace.GasUsed)
+		responseData["returnData"] = hexutil.Encode(trace.Output)
+		if trace.Error != "" {
+			responseData["error"] = trace.Error
+			if revert := decodeRevert(trace.Output); revert != nil {
+				responseData["revert"] = revert
+				responseData["revertReason"] = revert.Reason
+			} else if trace.RevertReason != "" {
+				responseData["revertReason"] = trace.RevertReason
+			}
+		}
+
+		var logs []callLog
+		collectLogs(trace, &logs)
+		transfers := make([]tokenTransfer, 0)
+		formattedLogs := make([]map[string]interface{}, 0, len(logs))
+		for _, l := range logs {
+			topics := make([]string, len(l.Topics))
+			for i, t := range l.Topics {
+				topics[i] = t.Hex()
+			}
+			formattedLogs = append(formattedLogs, map[string]interface{}{
+				"address": l.Address.Hex(),
+				"topics":  topics,
+				"data":    hexutil.Encode(l.Data),
+			})
+			if t, ok := decodeERC20Transfer(l.Address, l.Topics, l.Data); ok {
+				transfers = append(transfers, t)
+			}
+		}
+		responseData["logs"] = formattedLogs
+		responseData["tokenTransfers"] = transfers
+		responseData["balanceChanges"] = senderBalanceChanges(from, trace, transfers)
+		responseData["trace"] = trace
+	} else {
+		s.logger.Debug("debug_traceCall unavailable, falling back to eth_call", "error", traceErr)
+		responseData["method"] = "eth_call"
+		responseData["note"] = "The RPC does not support debug_traceCall; logs and balance changes are unavailable. Pass an rpcUrl with the debug namespace enabled for a full trace."
+
+		msg := ethereum.CallMsg{From: from, To: &to, Data: data, Value: value, Gas: gasLimit.Uint64()}
+		output, callErr := client.CallContract(ctx, msg, nil)
+		if callErr != nil {
+			responseData["success"] = false
+			responseData["error"] = callErr.Error()
+			if revert := revertFromError(callErr); revert != nil {
+				responseData["revert"] = revert
+				responseData["revertReason"] = revert.Reason
+			}
+		} else {
+			responseData["success"] = true
+			responseData["returnData"] = hexutil.Encode(output)
+
+			msg.Gas = 0
+			if gas, err := client.EstimateGas(ctx, msg); err == nil {
+				responseData["gasUsed"] = gas
+			}
+		}
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// collectLogs flattens the logs of a call tree in execution order. Logs of reverted
+// frames are dropped since they do not survive in the final state.
+func collectLogs(frame callFrame, logs *[]callLog) {
+	if frame.Error != "" {
+		return
+	}
+	*logs = append(*logs, frame.Logs...)
+	for _, c := range frame.Calls {
+		collectLogs(c, logs)
+	}
+}
+
+// senderBalanceChanges computes the net native and ERC20 balance change of the sender.
+// Gas costs are not included.
+func senderBalanceChanges(sender common.Address, trace callFrame, transfers []tokenTransfer) map[string]string {
+	changes := make(map[string]*big.Int)
+	add := func(token string, delta *big.Int) {
+		if _, ok := changes[token]; !ok {
+			changes[token] = new(big.Int)
+		}
+		changes[token].Add(changes[token], delta)
+	}
+
+	var walk func(frame callFrame)
+	walk = func(frame callFrame) {
+		if frame.Error != "" {
+			return
+		}
+		if frame.Value != nil && frame.Type != "DELEGATECALL" {
+			v := frame.Value.ToInt()
+			if strings.EqualFold(frame.From, sender.Hex()) {
+				add(ZeroAddress, new(big.Int).Neg(v))
+			}
+			if strings.EqualFold(frame.To, sender.Hex()) {
+				add(ZeroAddress, v)
+			}
+		}
+		for _, c := range frame.Calls {
+			walk(c)
+		}
+	}
+	walk(trace)
+
+	for _, t := range transfers {
+		amount, _ := new(big.Int).SetString(t.Amount, 10)
+		if strings.EqualFold(t.From, sender.Hex()) {
+			add(t.Token, new(big.Int).Neg(amount))
+		}
+		if strings.EqualFold(t.To, sender.Hex()) {
+			add(t.Token, amount)
+		}
+	}
+
+	result := make(map[string]string, len(changes))
+	for token, delta := range changes {
+		if delta.Sign() != 0 {
+			result[token] = delta.String()
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultSolanaChain is used to resolve the RPC URL when no chain is given
+	defaultSolanaChain = "sol"
+
+	// solanaNativeDecimals is the number of decimals of SOL (lamports per SOL = 1e9)
+	solanaNativeDecimals = 9
+)
+
+// Read-only Solana (SVM) handlers. Solana JSON-RPC is plain JSON-RPC 2.0 over HTTP,
+// so the generic go-ethereum rpc client is used for transport.
+
+// solanaBalanceResult is the response shape of getBalance
+type solanaBalanceResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value uint64 `json:"value"`
+}
+
+// solanaTokenAccountsResult is the response shape of getTokenAccountsByOwner with jsonParsed encoding
+type solanaTokenAccountsResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value []struct {
+		Pubkey  string `json:"pubkey"`
+		Account struct {
+			Data struct {
+				Parsed struct {
+					Info struct {
+						Mint        string `json:"mint"`
+						TokenAmount struct {
+							Amount   string `json:"amount"`
+							Decimals int    `json:"decimals"`
+						} `json:"tokenAmount"`
+					} `json:"info"`
+				} `json:"parsed"`
+			} `json:"data"`
+		} `json:"account"`
+	} `json:"value"`
+}
+
+// dialSolana resolves the Solana RPC URL and connects to it
+func (s *Server) dialSolana(ctx context.Context, chain, rpcUrl, apiKey string) (*rpc.Client, error) {
+	if chain == "" && rpcUrl == "" {
+		chain = defaultSolanaChain
+	}
+
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.DialContext(ctx, resolvedRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Solana RPC: %v", err)
+	}
+	return client, nil
+}
+
+func (s *Server) getSolanaBalanceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	address := getStringArg(request, "address")
+
+	if err := ValidateSolanaAddress("address", address); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := s.dialSolana(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer client.Close()
+
+	var balance solanaBalanceResult
+	if err := client.CallContext(ctx, &balance, "getBalance", address, map[string]string{"commitment": "confirmed"}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get balance: %v", err)), nil
+	}
+
+	// Format the result
+	result := map[string]interface{}{
+		"address":     address,
+		"balance":     new(big.Int).SetUint64(balance.Value).String(),
+		"tokenSymbol": "SOL",
+		"decimals":    solanaNativeDecimals,
+		"slot":        balance.Context.Slot,
+	}
+
+	jsonResult, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResult)), nil
+}
+
+func (s *Server) getSplTokenBalanceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	mintAddress := getStringArg(request, "mintAddress")
+	walletAddress := getStringArg(request, "walletAddress")
+
+	if err := ValidateSolanaAddress("mintAddress", mintAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateSolanaAddress("walletAddress", walletAddress); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := s.dialSolana(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer client.Close()
+
+	var accounts solanaTokenAccountsResult
+	err = client.CallContext(ctx, &accounts, "getTokenAccountsByOwner",
+		walletAddress,
+		map[string]string{"mint": mintAddress},
+		map[string]string{"encoding": "jsonParsed", "commitment": "confirmed"},
+	)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get token accounts: %v", err)), nil
+	}
+
+	// A wallet can hold the same mint in several token accounts; sum them
+	total := new(big.Int)
+	decimals := 0
+	tokenAccounts := make([]string, 0, len(accounts.Value))
+	for _, acc := range accounts.Value {
+		amount, ok := new(big.Int).SetString(acc.Account.Data.Parsed.Info.TokenAmount.Amount, 10)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to parse amount of token account %s", acc.Pubkey)), nil
+		}
+		total.Add(total, amount)
+		decimals = acc.Account.Data.Parsed.Info.TokenAmount.Decimals
+		tokenAccounts = append(tokenAccounts, acc.Pubkey)
+	}
+
+	// Format the result
+	responseData := map[string]interface{}{
+		"walletAddress": walletAddress,
+		"mintAddress":   mintAddress,
+		"balance":       total.String(),
+		"tokenAccounts": tokenAccounts,
+		"slot":          accounts.Context.Slot,
+	}
+	if len(tokenAccounts) > 0 {
+		responseData["decimals"] = decimals
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultToolTimeout bounds a tool call when no more specific timeout matches it
+const DefaultToolTimeout = time.Minute
+
+// defaultToolTimeouts give the tools that wait on the chain room for their own
+// timeoutSeconds maximum, plus a margin for the final lookups
+var defaultToolTimeouts = []ToolTimeout{
+	{Pattern: "track-transfer", Timeout: maxTrackTimeout + time.Minute},
+	{Pattern: "wait-for-receipt", Timeout: maxReceiptTimeout + time.Minute},
+	{Pattern: "*", Timeout: DefaultToolTimeout},
+}
+
+// ToolTimeout bounds the calls to tools matching a name pattern. A zero Timeout
+// leaves the matching tools unbounded.
+type ToolTimeout struct {
+	Pattern string
+	Timeout time.Duration
+}
+
+// String formats the timeout as pattern=duration, the form ParseToolTimeout accepts
+func (t ToolTimeout) String() string {
+	return fmt.Sprintf("%s=%s", t.Pattern, t.Timeout)
+}
+
+// ParseToolTimeout parses a --tool-timeout value such as "get-*=15s". Use "*" as the
+// pattern to change the default.
+func ParseToolTimeout(value string) (ToolTimeout, error) {
+	pattern, timeout, ok := strings.Cut(value, "=")
+	pattern = strings.TrimSpace(pattern)
+	if !ok || pattern == "" {
+		return ToolTimeout{}, fmt.Errorf("expected pattern=duration, got %q", value)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return ToolTimeout{}, fmt.Errorf("invalid tool pattern %q", pattern)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(timeout))
+	if err != nil || d < 0 {
+		return ToolTimeout{}, fmt.Errorf("invalid timeout %q in %q", timeout, value)
+	}
+	return ToolTimeout{Pattern: pattern, Timeout: d}, nil
+}
+
+// toolTimeout returns the timeout for a tool: the first configured pattern that
+// matches it, then the defaults
+func (s *Server) toolTimeout(name string) time.Duration {
+	for _, timeouts := range [][]ToolTimeout{s.toolTimeouts, defaultToolTimeouts} {
+		for _, t := range timeouts {
+			if ok, _ := path.Match(t.Pattern, name); ok {
+				return t.Timeout
+			}
+		}
+	}
+	return DefaultToolTimeout
+}
+
+// enforceToolTimeouts is tool handler middleware that runs each call under its tool's
+// timeout, so a hanging RPC dial or LI.FI request fails with the effective timeout
+// instead of holding the call open
+func (s *Server) enforceToolTimeouts(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout := s.toolTimeout(request.Params.Name)
+		if timeout <= 0 {
+			return next(ctx, request)
+		}
+
+		toolCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := next(toolCtx, request)
+
+		// Report failures caused by the deadline with the timeout that was in force
+		if errors.Is(toolCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			var detail string
+			switch {
+			case err != nil:
+				detail = err.Error()
+			case result != nil && result.IsError:
+				detail = resultText(result)
+			default:
+				return result, err
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s (the server's timeout for this tool): %s", request.Params.Name, timeout, detail)), nil
+		}
+		return result, err
+	}
+}
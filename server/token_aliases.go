@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// tokenArg pairs a token argument with the chain argument it lives on
+type tokenArg struct {
+	token string
+	chain string
+}
+
+// tokenArgs are the token arguments that accept symbols in place of addresses
+var tokenArgs = []tokenArg{
+	{token: "fromToken", chain: "fromChain"},
+	{token: "toToken", chain: "toChain"},
+	{token: "fromTokenAddress", chain: "fromChainId"},
+	{token: "toTokenAddress", chain: "toChainId"},
+}
+
+// bridgedTokenMarkers identify bridged variants in token names, which rank below the
+// chain's canonical token with the same symbol
+var bridgedTokenMarkers = []string{"bridged", "(pos)", "wormhole", "portal", "axelar", "multichain", "celer", "binance-peg"}
+
+// symbolCandidate is a token matching a symbol, with the attributes it is ranked by
+type symbolCandidate struct {
+	token   TokenListEntry
+	native  bool
+	bridged bool
+	priced  bool
+}
+
+// isTokenAddress reports whether v is an EVM or Solana address rather than a symbol
+func isTokenAddress(v string) bool {
+	if common.IsHexAddress(v) {
+		return true
+	}
+	decoded, ok := decodeBase58(v)
+	return ok && len(v) >= 32 && len(decoded) == 32
+}
+
+// resolveTokenSymbol resolves a token symbol on a chain to a token from the LI.FI token
+// list. When several tokens share the symbol the choice is deterministic: the native
+// token first, then tokens not marked as bridged, then priced tokens, then the lowest
+// address. The other candidates are returned as alternatives.
+func (s *Server) resolveTokenSymbol(ctx context.Context, chainID int, symbol, apiKey string) (*TokenListEntry, []TokenListEntry, error) {
+	tokens, err := s.tokenIndex.load(ctx, s, apiKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load token list: %v", err)
+	}
+
+	var candidates []symbolCandidate
+	for _, t := range tokens {
+		if t.ChainID != chainID || !strings.EqualFold(t.Symbol, symbol) {
+			continue
+		}
+		bridged := false
+		for _, marker := range bridgedTokenMarkers {
+			if strings.Contains(t.name, marker) {
+				bridged = true
+				break
+			}
+		}
+		price, _ := strconv.ParseFloat(t.PriceUSD, 64)
+		candidates = append(candidates, symbolCandidate{
+			token:   t.TokenListEntry,
+			native:  strings.EqualFold(t.Address, ZeroAddress),
+			bridged: bridged,
+			priced:  price > 0,
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("no token with symbol '%s' on chain %d", symbol, chainID)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.native != b.native {
+			return a.native
+		}
+		if a.bridged != b.bridged {
+			return !a.bridged
+		}
+		if a.priced != b.priced {
+			return a.priced
+		}
+		return strings.ToLower(a.token.Address) < strings.ToLower(b.token.Address)
+	})
+
+	alternatives := make([]TokenListEntry, 0, len(candidates)-1)
+	for _, c := range candidates[1:] {
+		alternatives = append(alternatives, c.token)
+	}
+	return &candidates[0].token, alternatives, nil
+}
+
+// resolveTokenSymbols is tool handler middleware that lets token arguments take symbols
+// such as "USDC" or "WETH". Symbols are resolved on the chain given by the paired chain
+// argument, and the resolution is added to JSON object results as tokenResolution.
+func (s *Server) resolveTokenSymbols(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]interface{})
+		if !ok {
+			return next(ctx, request)
+		}
+
+		apiKey := APIKeyFromContext(ctx)
+		var normalized map[string]interface{}
+		resolutions := make(map[string]interface{})
+		for _, ta := range tokenArgs {
+			symbol, _ := args[ta.token].(string)
+			symbol = strings.TrimSpace(symbol)
+			if symbol == "" || isTokenAddress(symbol) {
+				continue
+			}
+			// Chain identifiers have already been normalized to numeric IDs
+			chainStr, _ := args[ta.chain].(string)
+			chainID, err := strconv.Atoi(chainStr)
+			if err != nil {
+				continue
+			}
+			// UTXO chains name their native coin (e.g. "bitcoin") rather than use an address
+			if s.chainType(ctx, chainStr, apiKey) == ChainTypeUTXO {
+				continue
+			}
+
+			token, alternatives, err := s.resolveTokenSymbol(ctx, chainID, symbol, apiKey)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s: %v", ta.token, err)), nil
+			}
+
+			if normalized == nil {
+				normalized = make(map[string]interface{}, len(args))
+				for k, v := range args {
+					normalized[k] = v
+				}
+			}
+			normalized[ta.token] = token.Address
+
+			resolution := map[string]interface{}{
+				"symbol":  symbol,
+				"chainId": chainID,
+				"address": checksumAddress(token.Address),
+				"name":    token.Name,
+			}
+			if len(alternatives) > 0 {
+				others := make([]map[string]string, len(alternatives))
+				for i, alt := range alternatives {
+					others[i] = map[string]string{"address": alt.Address, "name": alt.Name}
+				}
+				resolution["alternatives"] = others
+			}
+			resolutions[ta.token] = resolution
+		}
+
+		if normalized == nil {
+			return next(ctx, request)
+		}
+		request.Params.Arguments = normalized
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+			return result, err
+		}
+
+		// Echo the resolved addresses so the caller can confirm them
+		tc, ok := mcp.AsTextContent(result.Content[0])
+		if !ok {
+			return result, err
+		}
+		var body map[string]interface{}
+		if json.Unmarshal([]byte(tc.Text), &body) != nil {
+			return result, err
+		}
+		body["tokenResolution"] = resolutions
+		jsonResponse, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return result, err
+		}
+		return mcp.NewToolResultText(string(jsonResponse)), nil
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seedTokenIndex fills the token index so resolveTokenSymbol never calls LI.FI
+func seedTokenIndex(s *Server, entries ...TokenListEntry) {
+	s.tokenIndex.mu.Lock()
+	defer s.tokenIndex.mu.Unlock()
+	tokens := make([]indexedToken, len(entries))
+	for i, t := range entries {
+		tokens[i] = newIndexedToken(t)
+	}
+	s.tokenIndex.tokens = tokens
+	s.tokenIndex.loadedAt = time.Now()
+}
+
+// aliasTestTokens has several tokens sharing a symbol on the same chain
+var aliasTestTokens = []TokenListEntry{
+	{ChainID: 1, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Symbol: "USDT", Name: "Tether USD", PriceUSD: "1"},
+	{ChainID: 1, Address: "0x0000000000000000000000000000000000000000", Symbol: "ETH", Name: "ETH", PriceUSD: "3000"},
+	{ChainID: 1, Address: "0x2170Ed0880ac9A755fd29B2688956BD959F933F8", Symbol: "ETH", Name: "Binance-Peg Ethereum Token", PriceUSD: "3000"},
+	{ChainID: 1, Address: "0x9999999999999999999999999999999999999999", Symbol: "USDC", Name: "USD Coin (Wormhole)", PriceUSD: "1"},
+	{ChainID: 1, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Symbol: "USDC", Name: "USD Coin", PriceUSD: "1"},
+	{ChainID: 1, Address: "0x0000000000000000000000000000000000000001", Symbol: "USDC", Name: "Fake USDC", PriceUSD: ""},
+	{ChainID: 1, Address: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Symbol: "DUP", Name: "Duplicate B", PriceUSD: "2"},
+	{ChainID: 1, Address: "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", Symbol: "DUP", Name: "Duplicate A", PriceUSD: "2"},
+	{ChainID: 1, Address: "0xcccccccccccccccccccccccccccccccccccccccc", Symbol: "dup", Name: "Duplicate C", PriceUSD: "2"},
+	{ChainID: 10, Address: "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", Symbol: "USDC", Name: "USD Coin", PriceUSD: "1"},
+}
+
+func TestResolveTokenSymbol(t *testing.T) {
+	tests := []struct {
+		chainID      int
+		symbol       string
+		want         string
+		alternatives []string
+	}{
+		// The native token beats an ERC20 with the same symbol
+		{1, "ETH", "0x0000000000000000000000000000000000000000", []string{"0x2170Ed0880ac9A755fd29B2688956BD959F933F8"}},
+		// Canonical beats bridged, and priced beats unpriced
+		{1, "usdc", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", []string{"0x0000000000000000000000000000000000000001", "0x9999999999999999999999999999999999999999"}},
+		// Otherwise the lowest address wins, compared case-insensitively
+		{1, "DUP", "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", []string{"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "0xcccccccccccccccccccccccccccccccccccccccc"}},
+		// Only tokens on the requested chain are considered
+		{10, "USDC", "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", nil},
+	}
+
+	// The choice must not depend on the order of the token list
+	orders := map[string][]TokenListEntry{
+		"as listed": aliasTestTokens,
+		"reversed":  reversedTokens(aliasTestTokens),
+		"rotated":   append(append([]TokenListEntry{}, aliasTestTokens[4:]...), aliasTestTokens[:4]...),
+	}
+	for name, tokens := range orders {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(t)
+			seedTokenIndex(s, tokens...)
+			for _, tt := range tests {
+				token, alternatives, err := s.resolveTokenSymbol(context.Background(), tt.chainID, tt.symbol, "")
+				if err != nil {
+					t.Fatalf("resolveTokenSymbol(%d, %q): %v", tt.chainID, tt.symbol, err)
+				}
+				if token.Address != tt.want {
+					t.Errorf("resolveTokenSymbol(%d, %q) = %s, want %s", tt.chainID, tt.symbol, token.Address, tt.want)
+				}
+				var got []string
+				for _, alt := range alternatives {
+					got = append(got, alt.Address)
+				}
+				if strings.Join(got, ",") != strings.Join(tt.alternatives, ",") {
+					t.Errorf("resolveTokenSymbol(%d, %q) alternatives = %v, want %v", tt.chainID, tt.symbol, got, tt.alternatives)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveTokenSymbolUnknown(t *testing.T) {
+	s := newTestServer(t)
+	seedTokenIndex(s, aliasTestTokens...)
+	if _, _, err := s.resolveTokenSymbol(context.Background(), 10, "DUP", ""); err == nil || !strings.Contains(err.Error(), "no token with symbol") {
+		t.Fatalf("error = %v, want no token with symbol", err)
+	}
+}
+
+func reversedTokens(tokens []TokenListEntry) []TokenListEntry {
+	reversed := make([]TokenListEntry, len(tokens))
+	for i, t := range tokens {
+		reversed[len(tokens)-1-i] = t
+	}
+	return reversed
+}
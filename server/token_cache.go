@@ -0,0 +1,264 @@
+package server
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTokenCacheSize is the number of tokens kept in the in-memory LRU
+	defaultTokenCacheSize = 10000
+
+	// tokenCacheFlushDelay is how long the writer waits after a change before persisting,
+	// so bursts of lookups are written to disk once
+	tokenCacheFlushDelay = 5 * time.Second
+)
+
+// tokenMetadata is the cached, immutable part of an ERC20 token's metadata
+type tokenMetadata struct {
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	Name     string `json:"name,omitempty"`
+	// NonStandard marks tokens whose symbol is a bytes32 (e.g. MKR) or that lack
+	// symbol() or decimals()
+	NonStandard bool `json:"nonStandard,omitempty"`
+	// DecimalsDefaulted marks tokens without decimals(), assumed to have 18
+	DecimalsDefaulted bool `json:"decimalsDefaulted,omitempty"`
+}
+
+// defaulted reports whether the symbol or decimals are placeholders. A failed symbol()
+// or decimals() call may be transient (a flaky RPC, a proxy not yet initialised), so
+// such metadata is never cached: a wrong 18 decimals would otherwise stick for good.
+func (m tokenMetadata) defaulted() bool {
+	return m.DecimalsDefaulted || m.Symbol == unknownTokenSymbol && m.NonStandard
+}
+
+// tokenCache is an LRU of token metadata keyed by (chainId, tokenAddress), optionally
+// persisted to a JSON file so restarts don't repeat the on-chain lookups
+type tokenCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+	path     string
+	logger   *slog.Logger
+
+	// dirty is signaled when the cache changes; flushLoop persists it after
+	// tokenCacheFlushDelay
+	dirty chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+type tokenCacheEntry struct {
+	key      string
+	metadata tokenMetadata
+}
+
+func newTokenCache(capacity int, path string, logger *slog.Logger) *tokenCache {
+	c := &tokenCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		path:     path,
+		logger:   logger,
+	}
+	if path != "" {
+		if err := c.load(); err != nil && !os.IsNotExist(err) {
+			logger.Warn("Failed to load token cache, starting empty", "path", path, "error", err)
+		}
+		c.dirty = make(chan struct{}, 1)
+		c.stop = make(chan struct{})
+		c.done = make(chan struct{})
+		go c.flushLoop()
+	}
+	return c
+}
+
+func tokenCacheKey(chainID int64, tokenAddress string) string {
+	return fmt.Sprintf("%d:%s", chainID, strings.ToLower(tokenAddress))
+}
+
+// get returns the cached metadata of a token and marks it as recently used
+func (c *tokenCache) get(chainID int64, tokenAddress string) (tokenMetadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[tokenCacheKey(chainID, tokenAddress)]
+	if !ok {
+		return tokenMetadata{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*tokenCacheEntry).metadata, true
+}
+
+// put caches the metadata of a token and schedules a write if a file is configured.
+// Defaulted metadata is not cached, so the token is read again on its next lookup.
+func (c *tokenCache) put(chainID int64, tokenAddress string, metadata tokenMetadata) {
+	if metadata.defaulted() {
+		return
+	}
+	c.mu.Lock()
+	c.set(tokenCacheKey(chainID, tokenAddress), metadata)
+	c.mu.Unlock()
+	c.markDirty()
+}
+
+// seed caches the tokens of a LI.FI token list
+func (c *tokenCache) seed(tokens map[string][]TokenListEntry) {
+	c.mu.Lock()
+	for _, list := range tokens {
+		for _, t := range list {
+			if t.Symbol == "" || t.Address == "" {
+				continue
+			}
+			c.set(tokenCacheKey(int64(t.ChainID), t.Address), tokenMetadata{Symbol: t.Symbol, Decimals: t.Decimals, Name: t.Name})
+		}
+	}
+	c.mu.Unlock()
+	c.markDirty()
+}
+
+// markDirty schedules a write of the cache file without waiting for it
+func (c *tokenCache) markDirty() {
+	if c.dirty == nil {
+		return
+	}
+	select {
+	case c.dirty <- struct{}{}:
+	default: // a write is already pending
+	}
+}
+
+// flushLoop persists the cache tokenCacheFlushDelay after it changes, and once more on
+// close if a change is still pending
+func (c *tokenCache) flushLoop() {
+	defer close(c.done)
+	for {
+		select {
+		case <-c.stop:
+			select {
+			case <-c.dirty:
+				c.persist()
+			default:
+			}
+			return
+		case <-c.dirty:
+		}
+
+		select {
+		case <-c.stop:
+			c.persist()
+			return
+		case <-time.After(tokenCacheFlushDelay):
+			c.persist()
+		}
+	}
+}
+
+// close stops the writer, persisting any pending changes first
+func (c *tokenCache) close() {
+	if c.dirty == nil {
+		return
+	}
+	c.once.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+}
+
+// set inserts or updates an entry, evicting the least recently used one if full.
+// Callers must hold c.mu.
+func (c *tokenCache) set(key string, metadata tokenMetadata) {
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*tokenCacheEntry).metadata = metadata
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&tokenCacheEntry{key: key, metadata: metadata})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*tokenCacheEntry).key)
+	}
+}
+
+// load reads the cache file. Entries are inserted oldest first so the file's order
+// (most recently used first) is preserved.
+func (c *tokenCache) load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+
+	var entries []struct {
+		Key string `json:"key"`
+		tokenMetadata
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse token cache: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(entries) - 1; i >= 0; i-- {
+		// Files written by earlier versions may hold defaulted metadata
+		if entries[i].defaulted() {
+			continue
+		}
+		c.set(entries[i].Key, entries[i].tokenMetadata)
+	}
+	return nil
+}
+
+// persist writes the cache to its file atomically. It is only called from flushLoop.
+// Failures are logged, not returned, since the disk layer is only an optimization.
+func (c *tokenCache) persist() {
+	type fileEntry struct {
+		Key string `json:"key"`
+		tokenMetadata
+	}
+
+	c.mu.Lock()
+	entries := make([]fileEntry, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		e := el.Value.(*tokenCacheEntry)
+		entries = append(entries, fileEntry{Key: e.key, tokenMetadata: e.metadata})
+	}
+	data, err := json.Marshal(entries)
+	c.mu.Unlock()
+	if err != nil {
+		c.logger.Warn("Failed to serialize token cache", "error", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		c.logger.Warn("Failed to create token cache directory", "path", c.path, "error", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".token-cache-*")
+	if err != nil {
+		c.logger.Warn("Failed to write token cache", "path", c.path, "error", err)
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		c.logger.Warn("Failed to write token cache", "path", c.path, "error", errors.Join(writeErr, closeErr))
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		c.logger.Warn("Failed to write token cache", "path", c.path, "error", err)
+	}
+}
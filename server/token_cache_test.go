@@ -0,0 +1,56 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenCachePersistsOnClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	usdc := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	c := newTokenCache(defaultTokenCacheSize, path, logger)
+	c.put(1, usdc, tokenMetadata{Symbol: "USDC", Decimals: 6})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("put wrote the cache file synchronously (stat error %v)", err)
+	}
+	c.close()
+
+	reloaded := newTokenCache(defaultTokenCacheSize, path, logger)
+	defer reloaded.close()
+	got, ok := reloaded.get(1, usdc)
+	if !ok || got.Symbol != "USDC" || got.Decimals != 6 {
+		t.Fatalf("reloaded %+v (found %v), want USDC with 6 decimals", got, ok)
+	}
+}
+
+func TestTokenCacheSkipsDefaultedMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	token := "0x1111111111111111111111111111111111111111"
+
+	// A file written before defaulted metadata stopped being cached
+	data := `[{"key":"1:` + token + `","symbol":"UNKNOWN","decimals":18,"nonStandard":true,"decimalsDefaulted":true}]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := newTokenCache(defaultTokenCacheSize, path, logger)
+	defer c.close()
+	if got, ok := c.get(1, token); ok {
+		t.Fatalf("loaded defaulted metadata %+v", got)
+	}
+
+	c.put(1, token, tokenMetadata{Symbol: "MKR", Decimals: 18, NonStandard: true, DecimalsDefaulted: true})
+	if got, ok := c.get(1, token); ok {
+		t.Fatalf("cached defaulted metadata %+v", got)
+	}
+	// A bytes32 symbol with real decimals is complete and cached
+	c.put(1, token, tokenMetadata{Symbol: "MKR", Decimals: 18, NonStandard: true})
+	if _, ok := c.get(1, token); !ok {
+		t.Fatal("bytes32 symbol metadata was not cached")
+	}
+}
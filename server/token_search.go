@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// tokenIndexTTL is how long the local token list used by search-tokens is reused
+	tokenIndexTTL = time.Hour
+
+	// Result limits for search-tokens
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// searchStopWords are query words that carry no meaning for matching
+var searchStopWords = map[string]bool{"on": true, "in": true, "the": true, "token": true, "tokens": true, "of": true}
+
+// indexedToken is a token list entry with precomputed lowercase search keys
+type indexedToken struct {
+	TokenListEntry
+	symbol string
+	name   string
+	words  []string
+}
+
+// tokenIndex is a local copy of the full LI.FI token list, loaded on first use and
+// refreshed after tokenIndexTTL, so searches don't pull megabytes through the model
+type tokenIndex struct {
+	mu       sync.Mutex
+	tokens   []indexedToken
+	loadedAt time.Time
+}
+
+// load returns the indexed tokens, fetching the token list if it is missing or stale.
+// A stale index is kept if the refresh fails.
+func (idx *tokenIndex) load(ctx context.Context, s *Server, apiKey string) ([]indexedToken, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.tokens != nil && time.Since(idx.loadedAt) < tokenIndexTTL {
+		return idx.tokens, nil
+	}
+
+	tokensByChain, err := s.fetchTokenList(ctx, nil, apiKey)
+	if err != nil {
+		if idx.tokens != nil {
+			s.logger.Warn("Token index refresh failed, serving stale index", "error", err)
+			return idx.tokens, nil
+		}
+		return nil, err
+	}
+
+	tokens := make([]indexedToken, 0, len(tokensByChain)*64)
+	for _, list := range tokensByChain {
+		for _, t := range list {
+			tokens = append(tokens, newIndexedToken(t))
+		}
+	}
+	idx.tokens = tokens
+	idx.loadedAt = time.Now()
+	return tokens, nil
+}
+
+// newIndexedToken prepares a token list entry for searching
+func newIndexedToken(t TokenListEntry) indexedToken {
+	name := strings.ToLower(t.Name)
+	return indexedToken{
+		TokenListEntry: t,
+		symbol:         strings.ToLower(t.Symbol),
+		name:           name,
+		words:          strings.FieldsFunc(name, func(r rune) bool { return !isAlphanumeric(r) }),
+	}
+}
+
+// isAlphanumeric reports whether r is an ASCII letter or digit
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// termScore scores how well one query term matches a token, or 0 if it doesn't
+func (t *indexedToken) termScore(term string) int {
+	switch {
+	case t.symbol == term:
+		return 100
+	case strings.HasPrefix(t.symbol, term):
+		return 60
+	case strings.Contains(t.symbol, term):
+		return 40
+	}
+	for _, w := range t.words {
+		if w == term {
+			return 30
+		}
+	}
+	if strings.Contains(t.name, term) {
+		return 20
+	}
+	// Tolerate a typo in longer terms
+	if len(term) >= 4 && editDistance(t.symbol, term) <= 1 {
+		return 25
+	}
+	for _, w := range t.words {
+		if len(term) >= 4 && editDistance(w, term) <= 1 {
+			return 15
+		}
+	}
+	return 0
+}
+
+// chainFromQuery removes a chain reference from the query terms and returns its ID.
+// A full chain name ("arbitrum") is recognized anywhere; a chain key ("arb") only
+// after "on" or "in", so token symbols like "eth" aren't mistaken for chains.
+func chainFromQuery(terms []string, chains []Chain) ([]string, int) {
+	for i, term := range terms {
+		afterOn := i > 0 && (terms[i-1] == "on" || terms[i-1] == "in")
+		for _, c := range chains {
+			name := strings.ToLower(c.Name)
+			if term == name || term == strings.ReplaceAll(name, " ", "") || (afterOn && (term == strings.ToLower(c.Key) || strings.HasPrefix(name, term+" "))) {
+				return append(append([]string{}, terms[:i]...), terms[i+1:]...), c.ID
+			}
+		}
+	}
+	return terms, 0
+}
+
+func (s *Server) searchTokensHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	query := strings.ToLower(strings.TrimSpace(getStringArg(request, "query")))
+	chain := getStringArg(request, "chain")
+	limit := mcp.ParseInt(request, "limit", defaultSearchLimit)
+
+	if query == "" {
+		return mcp.NewToolResultError("query parameter is required"), nil
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)), nil
+	}
+
+	// Load the chains cache to recognize chain names
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch chain data: %v", err)), nil
+	}
+	s.chains.mu.RLock()
+	chains := s.chains.data.Chains
+	s.chains.mu.RUnlock()
+
+	terms := strings.FieldsFunc(query, func(r rune) bool { return r == ' ' || r == ',' })
+	chainID := 0
+	if chain != "" {
+		id, err := strconv.Atoi(chain)
+		if err != nil {
+			for _, c := range chains {
+				if strings.EqualFold(c.Name, chain) || strings.EqualFold(c.Key, chain) {
+					id = c.ID
+					break
+				}
+			}
+		}
+		if id == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("chain '%s' not found", chain)), nil
+		}
+		chainID = id
+	} else {
+		terms, chainID = chainFromQuery(terms, chains)
+	}
+
+	var searchTerms []string
+	for _, t := range terms {
+		if !searchStopWords[t] {
+			searchTerms = append(searchTerms, t)
+		}
+	}
+	if len(searchTerms) == 0 {
+		return mcp.NewToolResultError("query has no token terms to search for (only a chain was recognized)"), nil
+	}
+
+	tokens, err := s.tokenIndex.load(ctx, s, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to load token list: %v", err)), nil
+	}
+
+	type match struct {
+		token *indexedToken
+		score int
+	}
+	var matches []match
+	for i := range tokens {
+		t := &tokens[i]
+		if chainID != 0 && t.ChainID != chainID {
+			continue
+		}
+		// Every term must match somewhere
+		score := 0
+		for _, term := range searchTerms {
+			ts := t.termScore(term)
+			if ts == 0 {
+				score = 0
+				break
+			}
+			score += ts
+		}
+		if score == 0 {
+			continue
+		}
+		// Priced tokens are more likely to be the real thing
+		if p, err := strconv.ParseFloat(t.PriceUSD, 64); err == nil && p > 0 {
+			score += 5
+		}
+		matches = append(matches, match{token: t, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].token.ChainID < matches[j].token.ChainID
+	})
+
+	results := make([]map[string]interface{}, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		results = append(results, map[string]interface{}{
+			"chainId":  m.token.ChainID,
+			"address":  checksumAddress(m.token.Address),
+			"symbol":   m.token.Symbol,
+			"name":     m.token.Name,
+			"decimals": m.token.Decimals,
+			"priceUSD": m.token.PriceUSD,
+			"score":    m.score,
+		})
+	}
+
+	responseData := map[string]interface{}{
+		"query":        query,
+		"totalMatches": len(matches),
+		"results":      results,
+	}
+	if chainID != 0 {
+		responseData["chainId"] = chainID
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
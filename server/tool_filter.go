@@ -0,0 +1,54 @@
+package server
+
+import (
+	"path"
+	"sort"
+)
+
+// toolFilter decides which tools are registered from glob patterns on tool names
+// (e.g. "get-*", "transfer-nft"). With no enable patterns every tool is enabled;
+// disable patterns are applied afterwards.
+type toolFilter struct {
+	enable  []string
+	disable []string
+	matched map[string]bool
+}
+
+// allows reports whether a tool is exposed, recording which patterns matched it
+func (f *toolFilter) allows(name string) bool {
+	enabled := len(f.enable) == 0
+	if f.match(f.enable, name) {
+		enabled = true
+	}
+	if f.match(f.disable, name) {
+		enabled = false
+	}
+	return enabled
+}
+
+// match reports whether name matches any of the patterns
+func (f *toolFilter) match(patterns []string, name string) bool {
+	found := false
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			if f.matched == nil {
+				f.matched = map[string]bool{}
+			}
+			f.matched[pattern] = true
+			found = true
+		}
+	}
+	return found
+}
+
+// unmatched returns the patterns that matched no tool, usually typos
+func (f *toolFilter) unmatched() []string {
+	var patterns []string
+	for _, pattern := range append(append([]string{}, f.enable...), f.disable...) {
+		if !f.matched[pattern] {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// maxToolLimitKeys is how many session/limit windows are tracked before expired ones
+// are swept
+const maxToolLimitKeys = 10000
+
+// ToolLimit is a call budget for the tools matching a name pattern: at most Calls
+// calls per Period, per session. Calls to every tool matching the pattern share the
+// budget, so "get-quote*" bounds get-quote, get-quote-to-amount and get-quote-with-calls
+// together.
+type ToolLimit struct {
+	Pattern string
+	Calls   int
+	Period  time.Duration
+}
+
+// String formats the limit as pattern=calls/period, the form ParseToolLimit accepts
+func (l ToolLimit) String() string {
+	return fmt.Sprintf("%s=%d/%s", l.Pattern, l.Calls, l.Period)
+}
+
+// ParseToolLimit parses a --tool-limit value such as "transfer-nft=20/24h"
+func ParseToolLimit(value string) (ToolLimit, error) {
+	pattern, budget, ok := strings.Cut(value, "=")
+	pattern = strings.TrimSpace(pattern)
+	if !ok || pattern == "" {
+		return ToolLimit{}, fmt.Errorf("expected pattern=calls/period, got %q", value)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return ToolLimit{}, fmt.Errorf("invalid tool pattern %q", pattern)
+	}
+	calls, period, ok := strings.Cut(strings.TrimSpace(budget), "/")
+	if !ok {
+		return ToolLimit{}, fmt.Errorf("expected pattern=calls/period, got %q", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(calls))
+	if err != nil || n <= 0 {
+		return ToolLimit{}, fmt.Errorf("invalid call count %q in %q", calls, value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return ToolLimit{}, fmt.Errorf("invalid period %q in %q", period, value)
+	}
+	return ToolLimit{Pattern: pattern, Calls: n, Period: d}, nil
+}
+
+// toolLimitKey identifies one budget window: a limit and the session it applies to
+type toolLimitKey struct {
+	limit   int
+	session string
+}
+
+// toolLimiter enforces ToolLimits with a sliding window of call times per session.
+// It is separate from the LI.FI HTTP rate limiter: it bounds how often an agent may
+// call a tool, whatever the tool does.
+type toolLimiter struct {
+	limits []ToolLimit
+
+	mu    sync.Mutex
+	calls map[toolLimitKey][]time.Time
+}
+
+func newToolLimiter(limits []ToolLimit) *toolLimiter {
+	return &toolLimiter{limits: limits, calls: map[toolLimitKey][]time.Time{}}
+}
+
+// allow records a call to tool in session if every matching budget has room. Otherwise
+// it records nothing and returns the exhausted limit and how long until a slot frees up.
+func (l *toolLimiter) allow(session, tool string, now time.Time) (*ToolLimit, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var matched []toolLimitKey
+	for i, limit := range l.limits {
+		if ok, _ := path.Match(limit.Pattern, tool); !ok {
+			continue
+		}
+		key := toolLimitKey{limit: i, session: session}
+		calls := l.prune(key, now)
+		if len(calls) >= limit.Calls {
+			return &l.limits[i], calls[0].Add(limit.Period).Sub(now)
+		}
+		matched = append(matched, key)
+	}
+
+	for _, key := range matched {
+		l.calls[key] = append(l.calls[key], now)
+	}
+	if len(l.calls) > maxToolLimitKeys {
+		for key := range l.calls {
+			l.prune(key, now)
+		}
+	}
+	return nil, 0
+}
+
+// prune drops calls that have left the key's window, forgetting the key when none remain
+func (l *toolLimiter) prune(key toolLimitKey, now time.Time) []time.Time {
+	calls := l.calls[key]
+	cutoff := now.Add(-l.limits[key.limit].Period)
+	i := 0
+	for i < len(calls) && !calls[i].After(cutoff) {
+		i++
+	}
+	calls = calls[i:]
+	if len(calls) == 0 {
+		delete(l.calls, key)
+	} else {
+		l.calls[key] = calls
+	}
+	return calls
+}
+
+// status reports the remaining budget of every limit for a session
+func (l *toolLimiter) status(session string, now time.Time) []map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	budgets := make([]map[string]interface{}, 0, len(l.limits))
+	for i, limit := range l.limits {
+		calls := l.prune(toolLimitKey{limit: i, session: session}, now)
+		budget := map[string]interface{}{
+			"pattern":   limit.Pattern,
+			"limit":     limit.Calls,
+			"period":    limit.Period.String(),
+			"remaining": limit.Calls - len(calls),
+		}
+		if len(calls) > 0 {
+			budget["resetsInSeconds"] = int64(calls[0].Add(limit.Period).Sub(now).Seconds())
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets
+}
+
+// toolLimitSession identifies the caller a budget applies to: the MCP session, or the
+// client's IP address for stateless HTTP, which has no session. The API key is never
+// used, since anonymous callers would share one budget and anyone could start a fresh
+// budget by sending a different key. A client can still open a new session, so budgets
+// contain a runaway agent loop rather than a caller set on getting around them.
+func toolLimitSession(ctx context.Context) string {
+	if session := mcpserver.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return "session:" + session.SessionID()
+	}
+	if addr := remoteAddrFromContext(ctx); addr != "" {
+		return "addr:" + addr
+	}
+	// In-process calls without a session share one budget
+	return "local"
+}
+
+// limitToolCalls is tool handler middleware that rejects calls over a tool's budget,
+// bounding the damage of a runaway agent loop
+func (s *Server) limitToolCalls(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.toolLimiter == nil {
+			return next(ctx, request)
+		}
+		limit, retryAfter := s.toolLimiter.allow(toolLimitSession(ctx), request.Params.Name, time.Now())
+		if limit != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("call budget exceeded for %s: at most %d calls per %s to tools matching %q; retry in %s",
+				request.Params.Name, limit.Calls, limit.Period, limit.Pattern, retryAfter.Round(time.Second))), nil
+		}
+		return next(ctx, request)
+	}
+}
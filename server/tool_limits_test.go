@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestToolLimitIgnoresAPIKey(t *testing.T) {
+	limiter := newToolLimiter([]ToolLimit{{Pattern: "get-quote*", Calls: 1, Period: time.Hour}})
+	now := time.Now()
+
+	request := func(remoteAddr, apiKey string) context.Context {
+		r := httptest.NewRequest("POST", "/mcp", nil)
+		r.RemoteAddr = remoteAddr
+		if apiKey != "" {
+			r.Header.Set("X-LiFi-Api-Key", apiKey)
+		}
+		return ExtractAPIKeyFromRequest(context.Background(), r)
+	}
+
+	if limit, _ := limiter.allow(toolLimitSession(request("192.0.2.1:1111", "")), "get-quote", now); limit != nil {
+		t.Fatal("first call rejected")
+	}
+	// A new key and connection from the same address doesn't reset the budget, which
+	// every get-quote* tool shares
+	if limit, _ := limiter.allow(toolLimitSession(request("192.0.2.1:2222", "other-key")), "get-quote-with-calls", now); limit == nil {
+		t.Fatal("changing the API key reset the budget")
+	}
+	// Another client has its own budget
+	if limit, _ := limiter.allow(toolLimitSession(request("192.0.2.2:1111", "")), "get-quote", now); limit != nil {
+		t.Fatal("another client's call rejected")
+	}
+}
@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// Polling schedule for track-transfer
+	defaultTrackTimeout      = 10 * time.Minute
+	maxTrackTimeout          = 30 * time.Minute
+	initialTrackPollInterval = 5 * time.Second
+	maxTrackPollInterval     = 30 * time.Second
+	trackPollBackoffFactor   = 1.5
+)
+
+// transferStatus is the subset of the /v1/status response used to drive polling
+type transferStatus struct {
+	Status           string `json:"status"`
+	Substatus        string `json:"substatus"`
+	SubstatusMessage string `json:"substatusMessage"`
+}
+
+// isFinal reports whether the transfer has reached a terminal state
+func (t transferStatus) isFinal() bool {
+	switch t.Status {
+	case "DONE", "FAILED", "INVALID":
+		return true
+	}
+	return false
+}
+
+// sendProgress emits an MCP progress notification if the client asked for one
+// by attaching a progress token to the request. Failures are ignored: progress
+// is best-effort and must never fail the tool call.
+func sendProgress(ctx context.Context, request mcp.CallToolRequest, progress float64, message string) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return
+	}
+	srv := mcpserver.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+	_ = srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": request.Params.Meta.ProgressToken,
+		"progress":      progress,
+		"message":       message,
+	})
+}
+
+func (s *Server) trackTransferHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	txHash := getStringArg(request, "txHash")
+	if txHash == "" {
+		return mcp.NewToolResultError("txHash parameter is required"), nil
+	}
+
+	// Get optional parameters
+	bridge := getStringArg(request, "bridge")
+	fromChain := getStringArg(request, "fromChain")
+	toChain := getStringArg(request, "toChain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	toAmountMin := getStringArg(request, "toAmountMin")
+	toAmount := getStringArg(request, "toAmount")
+	toToken := getStringArg(request, "toToken")
+	timeoutSeconds := mcp.ParseInt(request, "timeoutSeconds", int(defaultTrackTimeout/time.Second))
+
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	if timeout <= 0 || timeout > maxTrackTimeout {
+		return mcp.NewToolResultError(fmt.Sprintf("timeoutSeconds must be between 1 and %d", int(maxTrackTimeout/time.Second))), nil
+	}
+	if rpcUrl != "" {
+		if err := ValidateTxHash("txHash", txHash); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if toAmountMin != "" {
+		if err := ValidateAmountAllowZero("toAmountMin", toAmountMin); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if toAmount != "" {
+		if err := ValidateAmountAllowZero("toAmount", toAmount); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	// Build the query parameters
+	params := url.Values{}
+	params.Add("txHash", txHash)
+	if bridge != "" {
+		params.Add("bridge", bridge)
+	}
+	if fromChain != "" {
+		params.Add("fromChain", fromChain)
+	}
+	if toChain != "" {
+		params.Add("toChain", toChain)
+	}
+	requestURL := fmt.Sprintf("%s/v1/status?%s", BaseURL, params.Encode())
+
+	trackCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+
+	// With a source-chain RPC, wait for the source transaction to be mined before
+	// asking LI.FI, which reports NOT_FOUND until then. Over ws:// this is pushed
+	// by a newHeads subscription rather than polled.
+	var sourceBlock uint64
+	if rpcUrl != "" {
+		client, release, err := s.rpcClients.get(ctx, rpcUrl)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer release()
+		receipt, _, err := waitForReceipt(trackCtx, client, common.HexToHash(txHash), 1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("tracking canceled: %v", ctx.Err())), nil
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return mcp.NewToolResultError(fmt.Sprintf("timed out after %s waiting for the source transaction %s to be mined", timeout, txHash)), nil
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get source transaction receipt: %v", err)), nil
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return mcp.NewToolResultError(fmt.Sprintf("source transaction %s reverted in block %s; the transfer was not started", txHash, receipt.BlockNumber)), nil
+		}
+		sourceBlock = receipt.BlockNumber.Uint64()
+		sendProgress(ctx, request, 0, fmt.Sprintf("source transaction mined in block %d", sourceBlock))
+	}
+
+	interval := initialTrackPollInterval
+	var lastBody []byte
+	var last transferStatus
+
+	for poll := 1; ; poll++ {
+		body, err := s.httpClient.Get(trackCtx, requestURL, apiKey)
+		if err != nil {
+			if errors.Is(trackCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				break
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+		}
+
+		if err := json.Unmarshal(body, &last); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to parse status response: %v", err)), nil
+		}
+		lastBody = body
+
+		message := last.Status
+		if last.Substatus != "" {
+			message += " (" + last.Substatus + ")"
+		}
+		sendProgress(ctx, request, float64(poll), message)
+
+		if last.isFinal() {
+			break
+		}
+
+		select {
+		case <-trackCtx.Done():
+		case <-time.After(interval):
+		}
+		if trackCtx.Err() != nil {
+			if ctx.Err() != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("tracking canceled: %v", ctx.Err())), nil
+			}
+			break
+		}
+
+		interval = min(time.Duration(float64(interval)*trackPollBackoffFactor), maxTrackPollInterval)
+	}
+
+	if lastBody == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("timed out after %s before the first status response for %s", timeout, txHash)), nil
+	}
+
+	var statusData map[string]interface{}
+	if err := json.Unmarshal(lastBody, &statusData); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse status response: %v", err)), nil
+	}
+
+	responseData := map[string]interface{}{
+		"final":          last.isFinal(),
+		"timedOut":       !last.isFinal(),
+		"elapsedSeconds": int(time.Since(started).Seconds()),
+		"status":         statusData,
+	}
+	if sourceBlock > 0 {
+		responseData["sourceBlockNumber"] = sourceBlock
+	}
+	if last.Status == "DONE" {
+		if gas := s.destinationGas(ctx, statusData, apiKey); gas != nil {
+			responseData["destinationGas"] = gas
+		}
+	}
+	if last.Status == "DONE" && (toAmountMin != "" || toAmount != "") {
+		responseData["outputVerification"] = s.verifyOutput(ctx, statusData, toAmountMin, toAmount, toToken, apiKey)
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// destinationGas reports the recipient's native balance on the destination chain of a
+// completed transfer, including any gas refuel (fromAmountForGas) it received. It is
+// best effort: nil is returned when the status lacks an EVM recipient, and lookup
+// failures are reported in the result.
+func (s *Server) destinationGas(ctx context.Context, status map[string]interface{}, apiKey string) map[string]interface{} {
+	toAddress, _ := status["toAddress"].(string)
+	receiving, _ := status["receiving"].(map[string]interface{})
+	chainID, ok := receiving["chainId"].(float64)
+	if !ok || !common.IsHexAddress(toAddress) {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"chainId": int64(chainID),
+		"address": checksumAddress(toAddress),
+	}
+
+	rpcUrl, err := s.resolveRpcUrl(ctx, strconv.FormatInt(int64(chainID), 10), "", apiKey)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	defer release()
+	balance, err := client.BalanceAt(ctx, common.HexToAddress(toAddress), nil)
+	if err != nil {
+		result["error"] = fmt.Sprintf("failed to get balance: %v", err)
+		return result
+	}
+
+	result["nativeBalance"] = balance.String()
+	result["hasGas"] = balance.Sign() > 0
+	if symbol, decimals, err := s.getNativeTokenInfo(ctx, big.NewInt(int64(chainID)), apiKey); err == nil {
+		result["symbol"] = symbol
+		result["nativeBalanceFormatted"] = formatUnits(balance, decimals)
+	}
+	return result
+}
+
+// verifyOutput compares the amount a completed transfer delivered against the quote's
+// toAmountMin and toAmount. The received amount comes from the status receiving leg,
+// or from the ERC20 Transfer logs of the destination transaction when LI.FI omits it.
+func (s *Server) verifyOutput(ctx context.Context, status map[string]interface{}, toAmountMin, toAmount, toToken, apiKey string) map[string]interface{} {
+	receiving, _ := status["receiving"].(map[string]interface{})
+	token, _ := receiving["token"].(map[string]interface{})
+	tokenAddress, _ := token["address"].(string)
+	decimals, _ := token["decimals"].(float64)
+
+	result := map[string]interface{}{}
+	if symbol, ok := token["symbol"].(string); ok {
+		result["symbol"] = symbol
+	}
+	if tokenAddress != "" {
+		result["receivedToken"] = tokenAddress
+	}
+
+	// A refund or partial fill can deliver a different token; amounts are then not comparable
+	if toToken != "" && tokenAddress != "" && !strings.EqualFold(toToken, tokenAddress) {
+		result["receivedExpectedToken"] = false
+		result["warning"] = fmt.Sprintf("received %s instead of the quoted token %s; amounts cannot be compared", tokenAddress, toToken)
+		return result
+	}
+	if toToken != "" {
+		result["receivedExpectedToken"] = true
+	}
+
+	received, source, err := s.receivedAmount(ctx, status, apiKey)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["receivedAmount"] = received.String()
+	result["receivedAmountFormatted"] = formatUnits(received, int(decimals))
+	result["source"] = source
+
+	if toAmountMin != "" {
+		minimum, _ := new(big.Int).SetString(toAmountMin, 10)
+		shortfall := new(big.Int).Sub(minimum, received)
+		if shortfall.Sign() < 0 {
+			shortfall.SetInt64(0)
+		}
+		result["toAmountMin"] = minimum.String()
+		result["meetsMinimum"] = shortfall.Sign() == 0
+		result["shortfall"] = shortfall.String()
+		result["shortfallFormatted"] = formatUnits(shortfall, int(decimals))
+	}
+	if toAmount != "" {
+		quoted, _ := new(big.Int).SetString(toAmount, 10)
+		result["toAmount"] = quoted.String()
+		if quoted.Sign() > 0 {
+			// Positive slippage means less was received than quoted
+			diff := new(big.Float).SetInt(new(big.Int).Sub(quoted, received))
+			pct, _ := new(big.Float).Quo(diff, new(big.Float).SetInt(quoted)).Float64()
+			result["slippagePercent"] = pct * 100
+		}
+	}
+	return result
+}
+
+// receivedAmount returns the amount delivered to the recipient and where it was read from
+func (s *Server) receivedAmount(ctx context.Context, status map[string]interface{}, apiKey string) (*big.Int, string, error) {
+	receiving, _ := status["receiving"].(map[string]interface{})
+	if amount, ok := receiving["amount"].(string); ok && amount != "" {
+		received, ok := new(big.Int).SetString(amount, 10)
+		if !ok {
+			return nil, "", fmt.Errorf("invalid receiving amount in status: %s", amount)
+		}
+		return received, "status", nil
+	}
+
+	// Fall back to the destination receipt: sum the token transfers to the recipient
+	toAddress, _ := status["toAddress"].(string)
+	txHash, _ := receiving["txHash"].(string)
+	chainID, _ := receiving["chainId"].(float64)
+	token, _ := receiving["token"].(map[string]interface{})
+	tokenAddress, _ := token["address"].(string)
+	if !common.IsHexAddress(toAddress) || txHash == "" || chainID == 0 {
+		return nil, "", fmt.Errorf("status has no receiving amount or destination transaction")
+	}
+	if !common.IsHexAddress(tokenAddress) || common.HexToAddress(tokenAddress) == (common.Address{}) {
+		return nil, "", fmt.Errorf("status has no receiving amount, and native transfers cannot be read from receipt logs")
+	}
+
+	rpcUrl, err := s.resolveRpcUrl(ctx, strconv.FormatInt(int64(chainID), 10), "", apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get destination receipt: %v", err)
+	}
+
+	received := new(big.Int)
+	for _, log := range receipt.Logs {
+		transfer, ok := decodeERC20Transfer(log.Address, log.Topics, log.Data)
+		if !ok || !strings.EqualFold(transfer.Token, tokenAddress) || !strings.EqualFold(transfer.To, toAddress) {
+			continue
+		}
+		amount, _ := new(big.Int).SetString(transfer.Amount, 10)
+		received.Add(received, amount)
+	}
+	return received, "receipt-logs", nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) getTransactionHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	txHash := getStringArg(request, "txHash")
+
+	if err := ValidateTxHash("txHash", txHash); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	tx, pending, err := client.TransactionByHash(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("transaction %s not found on this chain", txHash)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get transaction: %v", err)), nil
+	}
+
+	// Get chain ID to include in the response
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	responseData := formatTransaction(tx, chainID.Int64())
+	responseData["chainId"] = chainID.String()
+	responseData["pending"] = pending
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+func (s *Server) getReceiptHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	chain := getStringArg(request, "chain")
+	rpcUrl := getStringArg(request, "rpcUrl")
+	txHash := getStringArg(request, "txHash")
+
+	if err := ValidateTxHash("txHash", txHash); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Resolve RPC URL from chain or use provided rpcUrl
+	resolvedRpcUrl, err := s.resolveRpcUrl(ctx, chain, rpcUrl, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rpcUrl = resolvedRpcUrl
+
+	// Get a pooled Ethereum client
+	client, release, err := s.rpcClients.get(ctx, rpcUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer release()
+
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("no receipt for %s: the transaction is pending or unknown on this chain (use wait-for-receipt to wait for it)", txHash)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get transaction receipt: %v", err)), nil
+	}
+
+	// Get chain ID to include in the response
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get chain ID: %v", err)), nil
+	}
+
+	responseData := formatReceipt(receipt)
+	responseData["chainId"] = chainID.String()
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+// formatTransaction converts a transaction into a JSON-friendly map with decoded fields
+func formatTransaction(tx *types.Transaction, chainID int64) map[string]interface{} {
+	result := map[string]interface{}{
+		"txHash": tx.Hash().Hex(),
+		"type":   tx.Type(),
+		"nonce":  tx.Nonce(),
+		"value":  tx.Value().String(),
+		"gas":    tx.Gas(),
+		"input":  hexutil.Encode(tx.Data()),
+	}
+
+	// The sender is cached from the RPC response, so the signer only matters as a fallback
+	signer := types.LatestSignerForChainID(tx.ChainId())
+	if tx.ChainId().Sign() == 0 {
+		signer = types.LatestSignerForChainID(big.NewInt(chainID))
+	}
+	if from, err := types.Sender(signer, tx); err == nil {
+		result["from"] = from.Hex()
+	}
+
+	if tx.To() != nil {
+		result["to"] = tx.To().Hex()
+	} else {
+		result["to"] = nil
+		result["contractCreation"] = true
+	}
+	if len(tx.Data()) >= 4 {
+		result["selector"] = hexutil.Encode(tx.Data()[:4])
+	}
+
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		result["gasPrice"] = tx.GasPrice().String()
+	} else {
+		result["maxFeePerGas"] = tx.GasFeeCap().String()
+		result["maxPriorityFeePerGas"] = tx.GasTipCap().String()
+	}
+
+	return result
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// transferStatuses are the status filters accepted by /v1/analytics/transfers
+var transferStatuses = map[string]bool{"ALL": true, "DONE": true, "PENDING": true, "FAILED": true}
+
+// parseTimestamp parses a unix timestamp in seconds, an RFC 3339 time or a YYYY-MM-DD date
+func parseTimestamp(field, value string) (int64, error) {
+	if ts, err := strconv.ParseInt(value, 10, 64); err == nil && ts >= 0 {
+		return ts, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.Unix(), nil
+	}
+	return 0, &ValidationError{Field: field, Message: fmt.Sprintf("expected a unix timestamp, an RFC 3339 time or a YYYY-MM-DD date: %s", value)}
+}
+
+// transferChainID returns the chainId of the sending or receiving side of a transfer
+func transferChainID(transfer map[string]interface{}, side string) string {
+	leg, _ := transfer[side].(map[string]interface{})
+	if id, ok := leg["chainId"].(float64); ok {
+		return strconv.FormatInt(int64(id), 10)
+	}
+	return ""
+}
+
+func (s *Server) getTransfersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	wallet := getStringArg(request, "wallet")
+	status := strings.ToUpper(getStringArg(request, "status"))
+	fromTime := getStringArg(request, "fromTimestamp")
+	toTime := getStringArg(request, "toTimestamp")
+	fromChain := getStringArg(request, "fromChain")
+	toChain := getStringArg(request, "toChain")
+	integrator := getStringArg(request, "integrator")
+
+	if wallet == "" {
+		return mcp.NewToolResultError("wallet parameter is required"), nil
+	}
+	if status == "" {
+		status = "ALL"
+	}
+	if !transferStatuses[status] {
+		return mcp.NewToolResultError(fmt.Sprintf("status must be one of ALL, DONE, PENDING or FAILED, got %q", status)), nil
+	}
+	if fromChain != "" {
+		if err := ValidateChainID("fromChain", fromChain); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if toChain != "" {
+		if err := ValidateChainID("toChain", toChain); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	// Build the query parameters
+	params := url.Values{}
+	params.Add("wallet", wallet)
+	params.Add("status", status)
+	if integrator != "" {
+		params.Add("integrator", integrator)
+	}
+
+	var fromTs, toTs int64
+	var err error
+	if fromTime != "" {
+		if fromTs, err = parseTimestamp("fromTimestamp", fromTime); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params.Add("fromTimestamp", strconv.FormatInt(fromTs, 10))
+	}
+	if toTime != "" {
+		if toTs, err = parseTimestamp("toTimestamp", toTime); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params.Add("toTimestamp", strconv.FormatInt(toTs, 10))
+	}
+	if fromTime != "" && toTime != "" && fromTs > toTs {
+		return mcp.NewToolResultError("fromTimestamp must not be after toTimestamp"), nil
+	}
+
+	// Make the request
+	requestURL := fmt.Sprintf("%s/v1/analytics/transfers?%s", BaseURL, params.Encode())
+	body, err := s.httpClient.Get(ctx, requestURL, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+	}
+
+	var result struct {
+		Transfers []map[string]interface{} `json:"transfers"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse transfers response: %v", err)), nil
+	}
+
+	// The endpoint has no chain filters, so those are applied here
+	transfers := make([]map[string]interface{}, 0, len(result.Transfers))
+	byStatus := make(map[string]int)
+	for _, t := range result.Transfers {
+		if fromChain != "" && transferChainID(t, "sending") != fromChain {
+			continue
+		}
+		if toChain != "" && transferChainID(t, "receiving") != toChain {
+			continue
+		}
+		transfers = append(transfers, t)
+		if st, ok := t["status"].(string); ok {
+			byStatus[st]++
+		}
+	}
+
+	responseData := map[string]interface{}{
+		"wallet":    checksumAddress(wallet),
+		"count":     len(transfers),
+		"byStatus":  byStatus,
+		"transfers": transfers,
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
+const (
+	// defaultPendingLookback is how far back find-pending-bridges looks when no since is given
+	defaultPendingLookback = 7 * 24 * time.Hour
+
+	// maxPendingChecks caps the /v1/status lookups made by one find-pending-bridges call
+	maxPendingChecks = 25
+)
+
+// pendingTransfer is a transfer the analytics endpoint reports as PENDING, rechecked
+// against /v1/status
+type pendingTransfer struct {
+	TxHash           string `json:"txHash"`
+	FromChain        string `json:"fromChain,omitempty"`
+	ToChain          string `json:"toChain,omitempty"`
+	Tool             string `json:"tool,omitempty"`
+	Status           string `json:"status,omitempty"`
+	Substatus        string `json:"substatus,omitempty"`
+	SubstatusMessage string `json:"substatusMessage,omitempty"`
+	SentAt           int64  `json:"sentAt,omitempty"`
+	ExplorerLink     string `json:"lifiExplorerLink,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// checkPendingTransfer looks a transfer up on /v1/status, which is updated before the
+// analytics endpoint, so a transfer that has since completed is not reported as stuck
+func (s *Server) checkPendingTransfer(ctx context.Context, transfer map[string]interface{}, apiKey string) pendingTransfer {
+	sending, _ := transfer["sending"].(map[string]interface{})
+	txHash, _ := sending["txHash"].(string)
+	pending := pendingTransfer{
+		TxHash:    txHash,
+		FromChain: transferChainID(transfer, "sending"),
+		ToChain:   transferChainID(transfer, "receiving"),
+		Status:    "PENDING",
+	}
+	pending.Tool, _ = transfer["tool"].(string)
+	pending.ExplorerLink, _ = transfer["lifiExplorerLink"].(string)
+	if ts, ok := sending["timestamp"].(float64); ok {
+		pending.SentAt = int64(ts)
+	}
+	if txHash == "" {
+		pending.Error = "transfer has no sending transaction hash"
+		return pending
+	}
+
+	params := url.Values{}
+	params.Add("txHash", txHash)
+	if pending.FromChain != "" {
+		params.Add("fromChain", pending.FromChain)
+	}
+	if pending.ToChain != "" {
+		params.Add("toChain", pending.ToChain)
+	}
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/status?%s", BaseURL, params.Encode()), apiKey)
+	if err != nil {
+		pending.Error = fmt.Sprintf("status check failed: %v", err)
+		return pending
+	}
+
+	var status transferStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		pending.Error = fmt.Sprintf("failed to parse status response: %v", err)
+		return pending
+	}
+	pending.Status = status.Status
+	pending.Substatus = status.Substatus
+	pending.SubstatusMessage = status.SubstatusMessage
+	return pending
+}
+
+func (s *Server) findPendingBridgesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey := APIKeyFromContext(ctx)
+
+	// Get parameters
+	wallet := getStringArg(request, "wallet")
+	since := getStringArg(request, "since")
+	fromChain := getStringArg(request, "fromChain")
+	integrator := getStringArg(request, "integrator")
+
+	if wallet == "" {
+		return mcp.NewToolResultError("wallet parameter is required"), nil
+	}
+	if fromChain != "" {
+		if err := ValidateChainID("fromChain", fromChain); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	sinceTs := time.Now().Add(-defaultPendingLookback).Unix()
+	if since != "" {
+		var err error
+		if sinceTs, err = parseTimestamp("since", since); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	// Build the query parameters
+	params := url.Values{}
+	params.Add("wallet", wallet)
+	params.Add("status", "PENDING")
+	params.Add("fromTimestamp", strconv.FormatInt(sinceTs, 10))
+	if integrator != "" {
+		params.Add("integrator", integrator)
+	}
+
+	// Make the request
+	requestURL := fmt.Sprintf("%s/v1/analytics/transfers?%s", BaseURL, params.Encode())
+	body, err := s.httpClient.Get(ctx, requestURL, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error making request: %v", err)), nil
+	}
+
+	var result struct {
+		Transfers []map[string]interface{} `json:"transfers"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse transfers response: %v", err)), nil
+	}
+
+	candidates := make([]map[string]interface{}, 0, len(result.Transfers))
+	for _, t := range result.Transfers {
+		if fromChain != "" && transferChainID(t, "sending") != fromChain {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	truncated := len(candidates) > maxPendingChecks
+	if truncated {
+		candidates = candidates[:maxPendingChecks]
+	}
+
+	// Recheck every candidate concurrently
+	checked := make([]pendingTransfer, len(candidates))
+	var wg sync.WaitGroup
+	for i, t := range candidates {
+		wg.Add(1)
+		go func(i int, t map[string]interface{}) {
+			defer wg.Done()
+			checked[i] = s.checkPendingTransfer(ctx, t, apiKey)
+		}(i, t)
+	}
+	wg.Wait()
+
+	// Transfers whose status could not be confirmed stay in the pending list with an error
+	pending := make([]pendingTransfer, 0, len(checked))
+	resolved := make([]pendingTransfer, 0)
+	for _, p := range checked {
+		if p.Error == "" && p.Status != "PENDING" && p.Status != "NOT_FOUND" {
+			resolved = append(resolved, p)
+			continue
+		}
+		pending = append(pending, p)
+	}
+
+	responseData := map[string]interface{}{
+		"wallet":    checksumAddress(wallet),
+		"since":     sinceTs,
+		"count":     len(pending),
+		"pending":   pending,
+		"resolved":  resolved,
+		"truncated": truncated,
+	}
+	if truncated {
+		responseData["note"] = fmt.Sprintf("only the first %d pending transfers were checked; narrow the range with since or fromChain", maxPendingChecks)
+	}
+	if len(pending) > 0 {
+		responseData["nextStep"] = "use track-transfer with a txHash to follow a transfer until it completes"
+	}
+
+	jsonResponse, err := json.Marshal(responseData)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
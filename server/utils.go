@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand/v2"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -14,98 +18,160 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// getTokenInfo retrieves token symbol and decimals for a given token contract
-func getTokenInfo(ctx context.Context, client *ethclient.Client, tokenAddress string) (string, int, error) {
+// requireContract checks with eth_getCode that a token address has contract code on the
+// client's chain. Calling an address without code succeeds with empty return data, which
+// otherwise surfaces as a confusing unpack error. tokenInfo runs it on cache misses, so
+// handlers that look up token metadata first get the check for free.
+func requireContract(ctx context.Context, client *ethclient.Client, token common.Address) error {
+	code, err := client.CodeAt(ctx, token, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get contract code for %s: %v", token.Hex(), err)
+	}
+	if len(code) == 0 {
+		return noContractError(token)
+	}
+	return nil
+}
+
+func noContractError(token common.Address) error {
+	return fmt.Errorf("token %s is not deployed on this chain: the address has no contract code (is it a token address from another chain?)", token.Hex())
+}
+
+// lacksContract reports whether token is known to have no contract code on the client's
+// chain. It costs an eth_getCode, so handlers call it only once a token call has failed,
+// to explain the failure.
+func lacksContract(ctx context.Context, client *ethclient.Client, token common.Address) bool {
+	code, err := client.CodeAt(ctx, token, nil)
+	return err == nil && len(code) == 0
+}
+
+// unknownTokenSymbol is reported for tokens without a readable symbol()
+const unknownTokenSymbol = "UNKNOWN"
+
+// decodeTokenSymbol decodes the return data of symbol(). Besides the standard string,
+// it accepts the bytes32 symbols of early tokens such as MKR and SAI, reporting them as
+// non-standard. ok is false if the data is neither.
+func decodeTokenSymbol(parsedABI abi.ABI, data []byte) (symbol string, nonStandard bool, ok bool) {
+	if err := parsedABI.UnpackIntoInterface(&symbol, "symbol", data); err == nil && symbol != "" {
+		return symbol, false, true
+	}
+	if len(data) != 32 {
+		return "", false, false
+	}
+	symbol = strings.TrimRight(string(data), "\x00")
+	if symbol == "" || strings.IndexFunc(symbol, func(r rune) bool { return r > unicode.MaxASCII || !unicode.IsPrint(r) }) >= 0 {
+		return "", false, false
+	}
+	return symbol, true, true
+}
+
+// getTokenInfo retrieves token symbol and decimals for a given token contract. Tokens
+// with a bytes32 symbol or without symbol() or decimals() are still described, with
+// NonStandard set and the missing values defaulted.
+func getTokenInfo(ctx context.Context, client *ethclient.Client, tokenAddress string) (tokenMetadata, error) {
 	tokenContract := common.HexToAddress(tokenAddress)
 
+	// Check for contract code first so a token from another chain gets a clear error
+	if err := requireContract(ctx, client, tokenContract); err != nil {
+		return tokenMetadata{}, err
+	}
+
 	// Parse the ERC20 ABI
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to parse ERC20 ABI: %v", err)
+		return tokenMetadata{}, fmt.Errorf("failed to parse ERC20 ABI: %v", err)
 	}
 
 	// Get symbol
 	symbolData, err := parsedABI.Pack("symbol")
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to pack symbol data: %v", err)
+		return tokenMetadata{}, fmt.Errorf("failed to pack symbol data: %v", err)
 	}
 
 	symbolResult, err := client.CallContract(ctx, ethereum.CallMsg{
 		To:   &tokenContract,
 		Data: symbolData,
 	}, nil)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to call symbol: %v", err)
+	if err != nil && !isRevert(err) {
+		return tokenMetadata{}, fmt.Errorf("failed to call symbol: %v", err)
 	}
 
-	var symbol string
-	err = parsedABI.UnpackIntoInterface(&symbol, "symbol", symbolResult)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to unpack symbol: %v", err)
+	metadata := tokenMetadata{Symbol: unknownTokenSymbol, NonStandard: true}
+	if symbol, nonStandard, ok := decodeTokenSymbol(parsedABI, symbolResult); err == nil && ok {
+		metadata.Symbol, metadata.NonStandard = symbol, nonStandard
 	}
 
 	// Get decimals
 	decimalsData, err := parsedABI.Pack("decimals")
 	if err != nil {
-		return symbol, 18, fmt.Errorf("failed to pack decimals data: %v", err)
+		return tokenMetadata{}, fmt.Errorf("failed to pack decimals data: %v", err)
 	}
 
 	decimalsResult, err := client.CallContract(ctx, ethereum.CallMsg{
 		To:   &tokenContract,
 		Data: decimalsData,
 	}, nil)
-	if err != nil {
-		return symbol, 18, fmt.Errorf("failed to call decimals: %v", err)
+	if err != nil && !isRevert(err) {
+		return tokenMetadata{}, fmt.Errorf("failed to call decimals: %v", err)
 	}
 
 	var decimals uint8
-	err = parsedABI.UnpackIntoInterface(&decimals, "decimals", decimalsResult)
-	if err != nil {
-		return symbol, 18, fmt.Errorf("failed to unpack decimals: %v", err)
+	if err != nil || parsedABI.UnpackIntoInterface(&decimals, "decimals", decimalsResult) != nil {
+		metadata.Decimals, metadata.NonStandard, metadata.DecimalsDefaulted = 18, true, true
+	} else {
+		metadata.Decimals = int(decimals)
 	}
 
-	return symbol, int(decimals), nil
+	return metadata, nil
+}
+
+// tokenInfo returns token symbol and decimals, served from the token cache when possible
+// and read on-chain otherwise. Only complete metadata is cached; see tokenMetadata.defaulted.
+func (s *Server) tokenInfo(ctx context.Context, client *ethclient.Client, chainID *big.Int, tokenAddress string) (tokenMetadata, error) {
+	if metadata, ok := s.tokenCache.get(chainID.Int64(), tokenAddress); ok {
+		return metadata, nil
+	}
+
+	metadata, err := getTokenInfo(ctx, client, tokenAddress)
+	if err != nil {
+		return metadata, err
+	}
+	s.tokenCache.put(chainID.Int64(), tokenAddress, metadata)
+	return metadata, nil
 }
 
 // getNativeTokenInfo returns the native token symbol and decimals for a given chain ID
 func (s *Server) getNativeTokenInfo(ctx context.Context, chainID *big.Int, apiKey string) (string, int, error) {
-	// Initialize chains cache if not already done
-	chainsCacheMu.RLock()
-	initialized := chainsCacheInitialized
-	chainsCacheMu.RUnlock()
-
-	if !initialized {
-		err := s.refreshChainsCache(ctx, apiKey)
-		if err != nil {
-			return "", 0, err
-		}
+	// Load the chains cache if it is missing or expired
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return "", 0, err
 	}
 
-	chainsCacheMu.RLock()
+	s.chains.mu.RLock()
 	// Look for the chain in the cache
 	chainIDInt := int(chainID.Int64())
-	for _, chain := range chainsCache.Chains {
+	for _, chain := range s.chains.data.Chains {
 		if chain.ID == chainIDInt {
 			// Some chains use nativeToken, others use nativeCurrency
 			if chain.NativeToken.Symbol != "" {
-				chainsCacheMu.RUnlock()
+				s.chains.mu.RUnlock()
 				return chain.NativeToken.Symbol, chain.NativeToken.Decimals, nil
 			}
 			if chain.NativeCurrency.Symbol != "" {
-				chainsCacheMu.RUnlock()
+				s.chains.mu.RUnlock()
 				return chain.NativeCurrency.Symbol, chain.NativeCurrency.Decimals, nil
 			}
 			// If neither is available, try getting from metamask
 			if chain.Metamask.ChainName != "" {
 				symbolParts := strings.Split(chain.Metamask.ChainName, " ")
 				if len(symbolParts) > 0 {
-					chainsCacheMu.RUnlock()
+					s.chains.mu.RUnlock()
 					return symbolParts[0], 18, nil
 				}
 			}
 		}
 	}
-	chainsCacheMu.RUnlock()
+	s.chains.mu.RUnlock()
 
 	// If chain not found in cache, try refreshing the cache once
 	err := s.refreshChainsCache(ctx, apiKey)
@@ -113,11 +179,11 @@ func (s *Server) getNativeTokenInfo(ctx context.Context, chainID *big.Int, apiKe
 		return "", 0, err
 	}
 
-	chainsCacheMu.RLock()
-	defer chainsCacheMu.RUnlock()
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
 
 	// Look again after refreshing
-	for _, chain := range chainsCache.Chains {
+	for _, chain := range s.chains.data.Chains {
 		if chain.ID == chainIDInt {
 			if chain.NativeToken.Symbol != "" {
 				return chain.NativeToken.Symbol, chain.NativeToken.Decimals, nil
@@ -140,7 +206,8 @@ func (s *Server) getNativeTokenInfo(ctx context.Context, chainID *big.Int, apiKe
 
 // resolveRpcUrl resolves an RPC URL from a chain identifier.
 // If rpcUrl is provided, it's returned directly.
-// If only chain is provided, looks up the RPC URL from chain data.
+// If only chain is provided, uses the configured override for the chain, if any,
+// and otherwise looks up a public RPC URL from chain data.
 // The chain parameter can be a numeric ID (e.g., "1") or a name (e.g., "ethereum").
 func (s *Server) resolveRpcUrl(ctx context.Context, chain, rpcUrl, apiKey string) (string, error) {
 	// If explicit RPC URL provided, use it
@@ -153,30 +220,31 @@ func (s *Server) resolveRpcUrl(ctx context.Context, chain, rpcUrl, apiKey string
 		return "", fmt.Errorf("either 'chain' or 'rpcUrl' parameter is required")
 	}
 
-	// Initialize chains cache if not already done
-	chainsCacheMu.RLock()
-	initialized := chainsCacheInitialized
-	chainsCacheMu.RUnlock()
-
-	if !initialized {
-		if err := s.refreshChainsCache(ctx, apiKey); err != nil {
-			return "", fmt.Errorf("failed to load chain data: %v", err)
+	// Configured overrides for numeric chain IDs don't need the chains cache
+	chainID, parseErr := strconv.Atoi(chain)
+	if parseErr == nil {
+		if url, ok := s.rpcOverrides[chainID]; ok {
+			return url, nil
 		}
 	}
 
-	chainsCacheMu.RLock()
-	defer chainsCacheMu.RUnlock()
+	// Load the chains cache if it is missing or expired
+	if err := s.ensureChainsCache(ctx, apiKey); err != nil {
+		return "", fmt.Errorf("failed to load chain data: %v", err)
+	}
+
+	s.chains.mu.RLock()
+	defer s.chains.mu.RUnlock()
 
-	// Try to parse as numeric chain ID first
-	chainID, err := strconv.Atoi(chain)
-	if err == nil {
+	// Look up numeric chain IDs first
+	if parseErr == nil {
 		// It's a numeric ID
-		for _, c := range chainsCache.Chains {
+		for _, c := range s.chains.data.Chains {
 			if c.ID == chainID {
-				if len(c.Metamask.RpcUrls) > 0 {
-					return c.Metamask.RpcUrls[0], nil
+				if url := s.registerRpcCandidates(c); url != "" {
+					return url, nil
 				}
-				return "", fmt.Errorf("chain %d has no RPC URLs configured", chainID)
+				return "", fmt.Errorf("chain %d has no usable public RPC URLs configured", chainID)
 			}
 		}
 		return "", fmt.Errorf("chain ID %d not found", chainID)
@@ -184,23 +252,89 @@ func (s *Server) resolveRpcUrl(ctx context.Context, chain, rpcUrl, apiKey string
 
 	// Try to match by name or key (case-insensitive)
 	chainLower := strings.ToLower(chain)
-	for _, c := range chainsCache.Chains {
+	for _, c := range s.chains.data.Chains {
 		if strings.ToLower(c.Name) == chainLower ||
 			strings.ToLower(c.Key) == chainLower ||
 			strings.ToLower(c.Metamask.ChainName) == chainLower {
-			if len(c.Metamask.RpcUrls) > 0 {
-				return c.Metamask.RpcUrls[0], nil
+			if url, ok := s.rpcOverrides[c.ID]; ok {
+				return url, nil
+			}
+			if url := s.registerRpcCandidates(c); url != "" {
+				return url, nil
 			}
-			return "", fmt.Errorf("chain '%s' has no RPC URLs configured", chain)
+			return "", fmt.Errorf("chain '%s' has no usable public RPC URLs configured", chain)
 		}
 	}
 
 	return "", fmt.Errorf("chain '%s' not found", chain)
 }
 
-// refreshChainsCache fetches the latest chain data from Li.Fi API
+// registerRpcCandidates returns the primary public RPC URL of a chain and registers
+// all of its usable public RPCs with the pool, so clients for it fail over between them
+func (s *Server) registerRpcCandidates(c Chain) string {
+	urls := publicRpcUrls(c.Metamask.RpcUrls)
+	if len(urls) == 0 {
+		return ""
+	}
+	// Failover speaks Ethereum JSON-RPC, so non-EVM chains keep a single endpoint
+	if c.ChainType != "SVM" {
+		s.rpcClients.setCandidates(urls[0], urls, int64(c.ID))
+	}
+	return urls[0]
+}
+
+// publicRpcUrls returns the RPC URLs from chain metadata that can be used without
+// credentials. URLs with unfilled placeholders (e.g. "${INFURA_API_KEY}") and
+// non-HTTP schemes are skipped.
+func publicRpcUrls(rpcUrls []string) []string {
+	var urls []string
+	for _, u := range rpcUrls {
+		u = strings.TrimSpace(u)
+		if !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+			continue
+		}
+		if strings.ContainsAny(u, "${}") {
+			continue
+		}
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// chainsRefreshTimeout bounds a shared chains refresh, which outlives the callers
+// waiting on it
+const chainsRefreshTimeout = 30 * time.Second
+
+// refreshChainsCache fetches the latest chain data from Li.Fi API. Concurrent callers
+// share a single request, so a cold start doesn't send one per tool call. The request
+// is detached from ctx so a caller giving up doesn't fail the others waiting on it.
 func (s *Server) refreshChainsCache(ctx context.Context, apiKey string) error {
-	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/chains?chainTypes=SVM,EVM", BaseURL), apiKey)
+	result := s.chains.refresh.DoChan("chains", func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), chainsRefreshTimeout)
+		defer cancel()
+
+		// Still abort when the server is closed
+		go func() {
+			select {
+			case <-s.stopRefresh:
+				cancel()
+			case <-fetchCtx.Done():
+			}
+		}()
+		return nil, s.fetchChains(fetchCtx, apiKey)
+	})
+
+	select {
+	case r := <-result:
+		return r.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchChains loads chain data from the LI.FI API into the cache
+func (s *Server) fetchChains(ctx context.Context, apiKey string) error {
+	body, err := s.httpClient.Get(ctx, fmt.Sprintf("%s/v1/chains?chainTypes=SVM,EVM,UTXO", BaseURL), apiKey)
 	if err != nil {
 		return fmt.Errorf("failed to fetch chains: %v", err)
 	}
@@ -211,9 +345,156 @@ func (s *Server) refreshChainsCache(ctx context.Context, apiKey string) error {
 		return fmt.Errorf("failed to parse chain data: %v", err)
 	}
 
-	chainsCacheMu.Lock()
-	chainsCache = chainData
-	chainsCacheInitialized = true
-	chainsCacheMu.Unlock()
+	s.chains.mu.Lock()
+	s.chains.data = chainData
+	s.chains.initialized = true
+	s.chains.updatedAt = time.Now()
+	s.chains.mu.Unlock()
 	return nil
 }
+
+// ensureChainsCache loads the chains cache if it has not been loaded or is older than
+// the configured TTL. If a refresh of an expired cache fails, the stale data is kept.
+func (s *Server) ensureChainsCache(ctx context.Context, apiKey string) error {
+	s.chains.mu.RLock()
+	initialized := s.chains.initialized
+	age := time.Since(s.chains.updatedAt)
+	s.chains.mu.RUnlock()
+
+	if initialized && (s.chainsCacheTTL <= 0 || age < s.chainsCacheTTL) {
+		return nil
+	}
+
+	err := s.refreshChainsCache(ctx, apiKey)
+	if err != nil && initialized {
+		s.logger.Warn("Failed to refresh expired chains cache, serving stale data", "age", age, "error", err)
+		return nil
+	}
+	return err
+}
+
+// refreshChainsPeriodically refreshes a loaded chains cache every TTL (with ±10% jitter)
+// until stop is closed, so long-running servers pick up new chains and RPC changes
+func (s *Server) refreshChainsPeriodically(stop <-chan struct{}) {
+	// Abort an in-flight refresh when the server is closed
+	base, cancelAll := context.WithCancel(context.Background())
+	defer cancelAll()
+	go func() {
+		<-stop
+		cancelAll()
+	}()
+
+	for {
+		jitter := time.Duration(float64(s.chainsCacheTTL) * 0.1 * (2*rand.Float64() - 1))
+		timer := time.NewTimer(s.chainsCacheTTL + jitter)
+
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Only refresh a cache that has been used; never fetch eagerly at startup
+		s.chains.mu.RLock()
+		initialized := s.chains.initialized
+		s.chains.mu.RUnlock()
+		if !initialized {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(base, 30*time.Second)
+		if err := s.refreshChainsCache(ctx, ""); err != nil {
+			s.logger.Warn("Background chains cache refresh failed", "error", err)
+		} else {
+			s.logger.Debug("Refreshed chains cache")
+		}
+		cancel()
+	}
+}
+
+// formatUnits converts an amount in base units into a decimal string (e.g. 1500000 with
+// 6 decimals becomes "1.5"). Trailing zeros in the fractional part are trimmed.
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	intPart := digits[:len(digits)-decimals]
+	fracPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	result := intPart
+	if fracPart != "" {
+		result += "." + fracPart
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// parseUnits converts a human-readable decimal string (e.g. "1.5") into base units.
+// It rejects amounts with more fractional digits than the token supports rather than
+// silently truncating them.
+func parseUnits(value string, decimals int) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("amount is required")
+	}
+	if strings.HasPrefix(value, "-") {
+		return nil, fmt.Errorf("amount cannot be negative")
+	}
+
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if intPart == "" && fracPart == "" {
+		return nil, fmt.Errorf("invalid amount format: %s", value)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return nil, fmt.Errorf("invalid amount format: %s", value)
+			}
+		}
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > decimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", value, decimals)
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart+strings.Repeat("0", decimals-len(fracPart)), "0")
+	if digits == "" {
+		return new(big.Int), nil
+	}
+	if len(digits) > MaxAmountDigits {
+		return nil, fmt.Errorf("amount exceeds maximum allowed digits")
+	}
+
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount format: %s", value)
+	}
+	return amount, nil
+}
+
+// usdValue computes amount (in base units) * priceUSD, returning 0 if the price is
+// unparseable or not finite
+func usdValue(amount *big.Int, decimals int, priceUSD string) float64 {
+	price, err := strconv.ParseFloat(priceUSD, 64)
+	if err != nil || price == 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0
+	}
+
+	value := new(big.Float).SetInt(amount)
+	value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	value.Mul(value, big.NewFloat(price))
+
+	f, _ := value.Float64()
+	return f
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	symbolSelector   = "0x95d89b41"
+	decimalsSelector = "0x313ce567"
+)
+
+// rpcReply is a fake node's answer to one JSON-RPC call: a result, or a revert when
+// revert is set
+type rpcReply struct {
+	result interface{}
+	revert bool
+}
+
+// newFakeRPC starts a JSON-RPC endpoint answering each call with reply. The eth_call
+// params are passed as the call's data, other methods get "".
+func newFakeRPC(t *testing.T, reply func(method, data string) rpcReply) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var data string
+		if req.Method == "eth_call" && len(req.Params) > 0 {
+			var call struct {
+				Data  string `json:"data"`
+				Input string `json:"input"`
+			}
+			json.Unmarshal(req.Params[0], &call)
+			data = call.Input + call.Data
+		}
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if rr := reply(req.Method, data); rr.revert {
+			response["error"] = map[string]interface{}{"code": 3, "message": "execution reverted", "data": "0x"}
+		} else {
+			response["result"] = rr.result
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	s := New(Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil)), ChainsCacheTTL: -1})
+	t.Cleanup(s.Close)
+	return s
+}
+
+func dialFakeRPC(t *testing.T, url string) *ethclient.Client {
+	t.Helper()
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// fakeTokenNode answers like a node hosting a 6-decimal USDC token on chain 1 where every
+// balance and allowance is 1000. getCodeCalls, if set, counts eth_getCode requests.
+func fakeTokenNode(getCodeCalls *atomic.Int32) func(method, data string) rpcReply {
+	symbol, _ := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack("USDC")
+	return func(method, data string) rpcReply {
+		switch method {
+		case "eth_chainId":
+			return rpcReply{result: "0x1"}
+		case "eth_blockNumber":
+			return rpcReply{result: "0x1"}
+		case "eth_getCode":
+			if getCodeCalls != nil {
+				getCodeCalls.Add(1)
+			}
+			return rpcReply{result: "0x6080"}
+		case "eth_call":
+			switch {
+			case strings.HasPrefix(data, symbolSelector):
+				return rpcReply{result: hexutil.Encode(symbol)}
+			case strings.HasPrefix(data, decimalsSelector):
+				return rpcReply{result: hexutil.Encode(common.LeftPadBytes([]byte{6}, 32))}
+			}
+			return rpcReply{result: hexutil.Encode(common.LeftPadBytes(big.NewInt(1000).Bytes(), 32))}
+		}
+		return rpcReply{result: nil}
+	}
+}
+
+// abiWord left-aligns s in a 32-byte word, the way bytes32 symbols are returned
+func abiWord(s string) []byte {
+	word := make([]byte, 32)
+	copy(word, s)
+	return word
+}
+
+func TestDecodeTokenSymbol(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringSymbol, err := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack("USDC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		data            []byte
+		want            string
+		wantNonStandard bool
+		wantOK          bool
+	}{
+		{"string", stringSymbol, "USDC", false, true},
+		{"bytes32", abiWord("MKR"), "MKR", true, true},
+		{"bytes32 zero", make([]byte, 32), "", false, false},
+		{"bytes32 not printable", abiWord("M\x01R"), "", false, false},
+		{"bytes32 not ASCII", abiWord("MK\xc3\xa9"), "", false, false},
+		{"empty", nil, "", false, false},
+		{"short", []byte{0x4d, 0x4b, 0x52}, "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbol, nonStandard, ok := decodeTokenSymbol(parsedABI, tt.data)
+			if symbol != tt.want || nonStandard != tt.wantNonStandard || ok != tt.wantOK {
+				t.Fatalf("got (%q, %v, %v), want (%q, %v, %v)", symbol, nonStandard, ok, tt.want, tt.wantNonStandard, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTokenInfoDefaults(t *testing.T) {
+	const token = "0x1111111111111111111111111111111111111111"
+	chainID := big.NewInt(1)
+
+	// The token has code, but symbol() and decimals() revert
+	var working atomic.Bool
+	url := newFakeRPC(t, func(method, data string) rpcReply {
+		switch {
+		case method == "eth_getCode":
+			return rpcReply{result: "0x6080"}
+		case method == "eth_call" && working.Load() && strings.HasPrefix(data, symbolSelector):
+			packed, _ := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack("USDC")
+			return rpcReply{result: hexutil.Encode(packed)}
+		case method == "eth_call" && working.Load() && strings.HasPrefix(data, decimalsSelector):
+			return rpcReply{result: hexutil.Encode(common.LeftPadBytes([]byte{6}, 32))}
+		}
+		return rpcReply{revert: true}
+	})
+	client := dialFakeRPC(t, url)
+	s := newTestServer(t)
+
+	metadata, err := s.tokenInfo(t.Context(), client, chainID, token)
+	if err != nil {
+		t.Fatalf("tokenInfo: %v", err)
+	}
+	want := tokenMetadata{Symbol: unknownTokenSymbol, Decimals: 18, NonStandard: true, DecimalsDefaulted: true}
+	if metadata != want {
+		t.Fatalf("got %+v, want %+v", metadata, want)
+	}
+	if cached, ok := s.tokenCache.get(1, token); ok {
+		t.Fatalf("defaulted metadata was cached: %+v", cached)
+	}
+
+	// Once the calls succeed the real metadata is read and cached
+	working.Store(true)
+	metadata, err = s.tokenInfo(t.Context(), client, chainID, token)
+	if err != nil {
+		t.Fatalf("tokenInfo: %v", err)
+	}
+	if metadata.Symbol != "USDC" || metadata.Decimals != 6 || metadata.NonStandard {
+		t.Fatalf("got %+v, want USDC with 6 decimals", metadata)
+	}
+	if _, ok := s.tokenCache.get(1, token); !ok {
+		t.Fatal("complete metadata was not cached")
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals int
+		want     string
+		wantErr  string
+	}{
+		{value: "1.5", decimals: 6, want: "1500000"},
+		{value: "1", decimals: 18, want: "1000000000000000000"},
+		{value: "0.000001", decimals: 6, want: "1"},
+		{value: ".5", decimals: 6, want: "500000"},
+		{value: "1.", decimals: 6, want: "1000000"},
+		{value: " 2 ", decimals: 0, want: "2"},
+		{value: "1.500", decimals: 1, want: "15"},
+		{value: "0", decimals: 18, want: "0"},
+		{value: "000.000", decimals: 6, want: "0"},
+		{value: "1.0", decimals: 0, want: "1"},
+		{value: "115792089237316195423570985008687907853269984665640564039457584007913129639935", decimals: 0,
+			want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{value: "0.0000001", decimals: 6, wantErr: "more than 6 decimal places"},
+		{value: "1.5", decimals: 0, wantErr: "more than 0 decimal places"},
+		{value: "-1", decimals: 6, wantErr: "cannot be negative"},
+		{value: "1e18", decimals: 18, wantErr: "invalid amount format"},
+		{value: "1E6", decimals: 0, wantErr: "invalid amount format"},
+		{value: "+1", decimals: 6, wantErr: "invalid amount format"},
+		{value: "1,000", decimals: 6, wantErr: "invalid amount format"},
+		{value: "1.2.3", decimals: 6, wantErr: "invalid amount format"},
+		{value: "0x10", decimals: 0, wantErr: "invalid amount format"},
+		{value: ".", decimals: 6, wantErr: "invalid amount format"},
+		{value: "", decimals: 6, wantErr: "amount is required"},
+		{value: strings.Repeat("9", 79), decimals: 0, wantErr: "exceeds maximum allowed digits"},
+		{value: strings.Repeat("9", 61), decimals: 18, wantErr: "exceeds maximum allowed digits"},
+	}
+	for _, tt := range tests {
+		got, err := parseUnits(tt.value, tt.decimals)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseUnits(%q, %d) error = %v, want %q", tt.value, tt.decimals, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseUnits(%q, %d) error = %v", tt.value, tt.decimals, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("parseUnits(%q, %d) = %s, want %s", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	huge, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	tests := []struct {
+		amount   *big.Int
+		decimals int
+		want     string
+	}{
+		{big.NewInt(1500000), 6, "1.5"},
+		{big.NewInt(1), 6, "0.000001"},
+		{big.NewInt(1000000), 6, "1"},
+		{big.NewInt(0), 18, "0"},
+		{big.NewInt(-1500000), 6, "-1.5"},
+		{big.NewInt(-1), 18, "-0.000000000000000001"},
+		{big.NewInt(42), 0, "42"},
+		{big.NewInt(-42), 0, "-42"},
+		{huge, 18, "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+		{huge, 0, huge.String()},
+	}
+	for _, tt := range tests {
+		if got := formatUnits(tt.amount, tt.decimals); got != tt.want {
+			t.Errorf("formatUnits(%s, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+
+	// Whatever formatUnits prints, parseUnits reads back
+	for _, tt := range tests {
+		if tt.amount.Sign() < 0 {
+			continue
+		}
+		back, err := parseUnits(tt.want, tt.decimals)
+		if err != nil || back.Cmp(tt.amount) != 0 {
+			t.Errorf("parseUnits(%q, %d) = %v, %v, want %s", tt.want, tt.decimals, back, err, tt.amount)
+		}
+	}
+}
+
+func TestUSDValue(t *testing.T) {
+	huge, _ := new(big.Int).SetString("1000000000000000000000000000000", 10) // 1e30
+	tests := []struct {
+		name     string
+		amount   *big.Int
+		decimals int
+		price    string
+		want     float64
+	}{
+		{"stablecoin", big.NewInt(1500000), 6, "1", 1.5},
+		{"ether", big.NewInt(2e18), 18, "3000.5", 6001},
+		{"no decimals", big.NewInt(3), 0, "2.5", 7.5},
+		{"negative amount", big.NewInt(-1000000), 6, "2", -2},
+		{"very large amount", huge, 18, "2", 2e12},
+		{"zero amount", big.NewInt(0), 18, "3000", 0},
+		{"empty price", big.NewInt(1e18), 18, "", 0},
+		{"unparseable price", big.NewInt(1e18), 18, "n/a", 0},
+		{"zero price", big.NewInt(1e18), 18, "0", 0},
+		{"NaN price", big.NewInt(1e18), 18, "NaN", 0},
+		{"infinite price", big.NewInt(0), 18, "Inf", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usdValue(tt.amount, tt.decimals, tt.price)
+			if math.Abs(got-tt.want) > 1e-9*(1+math.Abs(tt.want)) {
+				t.Fatalf("usdValue(%s, %d, %q) = %v, want %v", tt.amount, tt.decimals, tt.price, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -13,6 +14,11 @@ const (
 	// ZeroAddress is the Ethereum zero/burn address
 	ZeroAddress = "0x0000000000000000000000000000000000000000"
 
+	// Chain types as reported by LI.FI chain data
+	ChainTypeEVM  = "EVM"
+	ChainTypeSVM  = "SVM"
+	ChainTypeUTXO = "UTXO"
+
 	// MaxAmountDigits is the maximum number of digits allowed in an amount
 	// This prevents overflow attacks with extremely large numbers
 	MaxAmountDigits = 78 // uint256 max is ~78 digits
@@ -169,3 +175,199 @@ func ValidateTokenAddress(field, address string) error {
 	// Zero address is valid for native tokens
 	return nil
 }
+
+// ValidateTxHash validates a transaction hash (0x-prefixed, 32 bytes hex)
+func ValidateTxHash(field, hash string) error {
+	if hash == "" {
+		return &ValidationError{Field: field, Message: "transaction hash is required"}
+	}
+
+	if len(hash) != 66 || !strings.HasPrefix(hash, "0x") {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("invalid transaction hash format: %s", hash)}
+	}
+
+	for _, c := range hash[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("invalid transaction hash format: %s", hash)}
+		}
+	}
+
+	return nil
+}
+
+// base58Alphabet is the Bitcoin/Solana base58 alphabet
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58 decodes a base58 string, returning false if it contains invalid characters
+func decodeBase58(s string) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		idx := strings.IndexRune(base58Alphabet, c)
+		if idx < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	// Leading '1's encode leading zero bytes
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == '1' {
+		leadingZeros++
+	}
+
+	return append(make([]byte, leadingZeros), n.Bytes()...), true
+}
+
+// ValidateSolanaAddress validates a Solana address (base58-encoded 32-byte public key)
+func ValidateSolanaAddress(field, address string) error {
+	if address == "" {
+		return &ValidationError{Field: field, Message: "address is required"}
+	}
+
+	decoded, ok := decodeBase58(address)
+	if !ok || len(decoded) != 32 {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("invalid Solana address format: %s", address)}
+	}
+
+	return nil
+}
+
+// bech32Charset is the BIP-173 data character set
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Polymod computes the BIP-173 checksum polynomial over the expanded values
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// validSegwitAddress reports whether address is a mainnet (bc1) segwit address with a
+// valid bech32 (witness v0) or bech32m (v1+) checksum and program length
+func validSegwitAddress(address string) bool {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return false
+	}
+	address = strings.ToLower(address)
+	if !strings.HasPrefix(address, "bc1") || len(address) < 14 || len(address) > 90 {
+		return false
+	}
+
+	data := make([]byte, 0, len(address)-3)
+	for _, c := range address[3:] {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx < 0 {
+			return false
+		}
+		data = append(data, byte(idx))
+	}
+
+	// Expand the "bc" human-readable part and verify the checksum
+	values := []byte{3, 3, 0, 2, 3}
+	checksum := bech32Polymod(append(values, data...))
+	version := data[0]
+	switch {
+	case version == 0 && checksum != 1:
+		return false
+	case version > 0 && checksum != 0x2bc830a3:
+		return false
+	case version > 16:
+		return false
+	}
+
+	// Regroup the 5-bit program (without version and checksum) into bytes
+	var program []byte
+	acc, bits := 0, 0
+	for _, v := range data[1 : len(data)-6] {
+		acc = acc<<5 | int(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			program = append(program, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return false
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return false
+	}
+	return version != 0 || len(program) == 20 || len(program) == 32
+}
+
+// ValidateBitcoinAddress validates a Bitcoin mainnet address: legacy P2PKH (1...) and
+// P2SH (3...) base58check addresses, and bech32/bech32m segwit addresses (bc1...)
+func ValidateBitcoinAddress(field, address string) error {
+	if address == "" {
+		return &ValidationError{Field: field, Message: "address is required"}
+	}
+
+	if validSegwitAddress(address) {
+		return nil
+	}
+
+	// Base58check: version byte, 20-byte hash and a 4-byte double-SHA256 checksum
+	decoded, ok := decodeBase58(address)
+	if ok && len(decoded) == 25 && (decoded[0] == 0x00 || decoded[0] == 0x05) {
+		first := sha256.Sum256(decoded[:21])
+		second := sha256.Sum256(first[:])
+		if string(second[:4]) == string(decoded[21:]) {
+			return nil
+		}
+	}
+
+	return &ValidationError{Field: field, Message: fmt.Sprintf("invalid Bitcoin address format: %s", address)}
+}
+
+// ValidateAddressForChainType validates an address for a chain type from LI.FI chain
+// data. Chain types without a validator only require a non-empty address.
+func ValidateAddressForChainType(field, address, chainType string) error {
+	switch chainType {
+	case ChainTypeSVM:
+		return ValidateSolanaAddress(field, address)
+	case ChainTypeUTXO:
+		return ValidateBitcoinAddress(field, address)
+	case ChainTypeEVM, "":
+		return ValidateAddress(field, address)
+	}
+	if address == "" {
+		return &ValidationError{Field: field, Message: "address is required"}
+	}
+	return nil
+}
+
+// ValidateRecipientAddressForChainType validates a recipient address for a chain type,
+// rejecting the zero address on EVM chains
+func ValidateRecipientAddressForChainType(field, address, chainType string) error {
+	if chainType == ChainTypeEVM || chainType == "" {
+		return ValidateRecipientAddress(field, address)
+	}
+	return ValidateAddressForChainType(field, address, chainType)
+}
+
+// ValidateTokenAddressForChainType validates a token address for a chain type. On
+// UTXO chains LI.FI identifies the native coin by name (e.g. "bitcoin"), so any
+// non-empty value is accepted.
+func ValidateTokenAddressForChainType(field, address, chainType string) error {
+	switch chainType {
+	case ChainTypeEVM, "":
+		return ValidateTokenAddress(field, address)
+	case ChainTypeSVM:
+		return ValidateSolanaAddress(field, address)
+	}
+	if address == "" {
+		return &ValidationError{Field: field, Message: "token address is required"}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import "testing"
+
+func TestValidateBitcoinAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		valid   bool
+	}{
+		// BIP-173
+		{"v0 P2WPKH uppercase", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{"v0 P2WPKH", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", true},
+		{"v0 P2WSH", "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", true},
+		{"mixed case", "bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+		{"bad checksum", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false},
+		{"empty data", "bc1gmk9yu", false},
+		{"testnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", false},
+
+		// BIP-350
+		{"v1 taproot", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", true},
+		{"v1 40-byte program", "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", true},
+		{"v16", "BC1SW50QGDZ25J", true},
+		{"v2", "bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", true},
+		{"v0 with bech32m checksum", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", false},
+		{"v1 with bech32 checksum", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", false},
+		{"v17", "BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", false},
+		{"invalid character", "bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", false},
+		{"non-zero padding", "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", false},
+		{"v0 wrong program length", "bc1qr508d6qejxtdg4y5r3zarvaryvq37dmv", false},
+
+		// Base58check
+		{"P2PKH", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"P2SH", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"P2PKH bad checksum", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"P2SH bad checksum", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLz", false},
+		{"base58 invalid character", "1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBitcoinAddress("address", tt.address)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ValidateBitcoinAddress(%q) = %v, want valid=%v", tt.address, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateSolanaAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		valid   bool
+	}{
+		{"wallet", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", true},
+		{"system program", "11111111111111111111111111111111", true},
+		{"wrapped SOL", "So11111111111111111111111111111111111111112", true},
+		{"USDC mint", "EPjFWdd5AufqSZqDUqLqkAhmVMwr5BvhWqyHf6vDqxtY", true},
+		{"too short", "1111", false},
+		{"too long", "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM9WzD", false},
+		{"invalid character", "0WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", false},
+		{"EVM address", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSolanaAddress("address", tt.address)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ValidateSolanaAddress(%q) = %v, want valid=%v", tt.address, err, tt.valid)
+			}
+		})
+	}
+}